@@ -0,0 +1,108 @@
+package main
+
+import (
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/andrewsomething/do-api-slugs/api/internal/catalog"
+)
+
+const (
+	// aggregateParallelism bounds the number of concurrent upstream calls
+	// made by a single /catalog request.
+	aggregateParallelism = 3
+)
+
+// catalogResponse holds every resource in one response. Each section has its
+// own retrieved_at. Sections that couldn't be retrieved are omitted and their
+// errors listed, keyed by section name, so one failing upstream call doesn't
+// fail the whole response.
+type catalogResponse struct {
+	Sizes            *sizesResponse            `json:"sizes,omitempty"`
+	Regions          *regionsResponse          `json:"regions,omitempty"`
+	AppImages        *imageResponse            `json:"app_images,omitempty"`
+	DistroImages     *imageResponse            `json:"distro_images,omitempty"`
+	K8sOptions       *k8sResponse              `json:"k8s_options,omitempty"`
+	AppInstanceSizes *appInstanceSizesResponse `json:"app_instance_sizes,omitempty"`
+	DatabaseOptions  *databaseOptionsResponse  `json:"database_options,omitempty"`
+	Errors           map[string]string         `json:"errors,omitempty"`
+	RetrievedAt      string                    `json:"retrieved_at"`
+}
+
+// catalogSections stores each resource of a catalog in the response, keyed
+// by resource name, along with the time it was retrieved.
+var catalogSections = map[string]func(resp *catalogResponse, cat *catalog.Catalog, retrievedAt string){
+	catalog.ResourceSizes: func(resp *catalogResponse, cat *catalog.Catalog, retrievedAt string) {
+		resp.Sizes = &sizesResponse{Sizes: cat.Sizes, RetrievedAt: retrievedAt}
+	},
+	catalog.ResourceRegions: func(resp *catalogResponse, cat *catalog.Catalog, retrievedAt string) {
+		resp.Regions = &regionsResponse{Regions: cat.Regions, RetrievedAt: retrievedAt}
+	},
+	catalog.ResourceAppImages: func(resp *catalogResponse, cat *catalog.Catalog, retrievedAt string) {
+		resp.AppImages = &imageResponse{Images: cat.AppImages, RetrievedAt: retrievedAt}
+	},
+	catalog.ResourceDistroImages: func(resp *catalogResponse, cat *catalog.Catalog, retrievedAt string) {
+		resp.DistroImages = &imageResponse{Images: cat.DistroImages, RetrievedAt: retrievedAt}
+	},
+	catalog.ResourceK8sOptions: func(resp *catalogResponse, cat *catalog.Catalog, retrievedAt string) {
+		resp.K8sOptions = &k8sResponse{Options: cat.K8sOptions, RetrievedAt: retrievedAt}
+	},
+	catalog.ResourceAppInstanceSizes: func(resp *catalogResponse, cat *catalog.Catalog, retrievedAt string) {
+		resp.AppInstanceSizes = &appInstanceSizesResponse{Sizes: cat.AppInstanceSizes, RetrievedAt: retrievedAt}
+	},
+	catalog.ResourceDatabaseOptions: func(resp *catalogResponse, cat *catalog.Catalog, retrievedAt string) {
+		resp.DatabaseOptions = &databaseOptionsResponse{Options: cat.DatabaseOptions, RetrievedAt: retrievedAt}
+	},
+}
+
+// aggregateCatalog handles /catalog, retrieving every resource concurrently
+// with at most aggregateParallelism upstream calls in flight. It only fails
+// if every resource does.
+func (h *handler) aggregateCatalog(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := h.upstreamContext(r)
+	defer cancel()
+
+	var resp catalogResponse
+	var lastErr error
+	var mu sync.Mutex
+	cat := &catalog.Catalog{}
+	sem := make(chan struct{}, aggregateParallelism)
+	var wg sync.WaitGroup
+	for _, res := range catalog.Resources {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sem <- struct{}{}
+			store, err := res.Fetch(ctx, h.client)
+			<-sem
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				loggerFrom(ctx).Warn("catalog section failed", "section", res.Name, "error", err)
+				lastErr = err
+				if resp.Errors == nil {
+					resp.Errors = make(map[string]string)
+				}
+				resp.Errors[res.Name] = err.Error()
+				return
+			}
+			store(cat)
+		}()
+	}
+	wg.Wait()
+
+	if len(resp.Errors) == len(catalog.Resources) {
+		h.writeUpstreamError(w, r, lastErr)
+		return
+	}
+	for _, res := range catalog.Resources {
+		if _, ok := cat.ResourceRetrievedAt(res.Name); ok {
+			catalogSections[res.Name](&resp, cat, retrievedAt(cat, res.Name))
+		}
+	}
+	resp.RetrievedAt = time.Now().Format("Mon Jan _2 15:04:05 2006 UTC")
+
+	writeResponse(w, r, resp)
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/andrewsomething/do-api-slugs/api/internal/catalog"
+)
+
+func TestAggregateCatalog(t *testing.T) {
+	tests := []struct {
+		name string
+		// failing lists the upstream paths that respond with a 500.
+		failing map[string]bool
+		status  int
+		errors  []string
+	}{
+		{
+			name:   "every section",
+			status: http.StatusOK,
+		},
+		{
+			name:    "partial failure",
+			failing: map[string]bool{"/v2/kubernetes/options": true, "/v2/databases/options": true},
+			status:  http.StatusOK,
+			errors:  []string{catalog.ResourceK8sOptions, catalog.ResourceDatabaseOptions},
+		},
+		{
+			name: "every section failed",
+			failing: map[string]bool{
+				"/v2/sizes":                     true,
+				"/v2/regions":                   true,
+				"/v2/images":                    true,
+				"/v2/kubernetes/options":        true,
+				"/v2/apps/tiers/instance_sizes": true,
+				"/v2/databases/options":         true,
+			},
+			status: http.StatusBadGateway,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				if tt.failing[r.URL.Path] {
+					w.WriteHeader(http.StatusInternalServerError)
+					w.Write([]byte(`{"id":"server_error","message":"boom"}`))
+					return
+				}
+				w.Write([]byte(`{"sizes":[{"slug":"s-1vcpu-1gb"}],"regions":[{"slug":"nyc1"}]}`))
+			}))
+			h := &handler{client: client, requestTimeout: 5 * time.Second}
+
+			rec := httptest.NewRecorder()
+			h.aggregateCatalog(rec, httptest.NewRequest(http.MethodGet, "/catalog", nil))
+
+			if rec.Code != tt.status {
+				t.Fatalf("got status %d, want %d", rec.Code, tt.status)
+			}
+			if tt.status != http.StatusOK {
+				var resp errorResponse
+				if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+					t.Fatal(err)
+				}
+				if resp.Message != "upstream request failed" {
+					t.Errorf("got %+v", resp)
+				}
+				return
+			}
+
+			var resp catalogResponse
+			if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+				t.Fatal(err)
+			}
+			if len(resp.Errors) != len(tt.errors) {
+				t.Errorf("got errors %v, want errors for %v", resp.Errors, tt.errors)
+			}
+			sections := map[string]bool{
+				catalog.ResourceSizes:            resp.Sizes != nil && len(resp.Sizes.Sizes) == 1 && resp.Sizes.RetrievedAt != "",
+				catalog.ResourceRegions:          resp.Regions != nil && len(resp.Regions.Regions) == 1 && resp.Regions.RetrievedAt != "",
+				catalog.ResourceAppImages:        resp.AppImages != nil,
+				catalog.ResourceDistroImages:     resp.DistroImages != nil,
+				catalog.ResourceK8sOptions:       resp.K8sOptions != nil,
+				catalog.ResourceAppInstanceSizes: resp.AppInstanceSizes != nil,
+				catalog.ResourceDatabaseOptions:  resp.DatabaseOptions != nil,
+			}
+			for name, present := range sections {
+				failed := contains(tt.errors, name)
+				if present == failed || (resp.Errors[name] != "") != failed {
+					t.Errorf("%s: got section %v with error %q, want failed %v", name, present, resp.Errors[name], failed)
+				}
+			}
+		})
+	}
+}
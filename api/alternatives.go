@@ -0,0 +1,154 @@
+package main
+
+import (
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+
+	"github.com/andrewsomething/do-api-slugs/api/internal/catalog"
+	"github.com/digitalocean/godo"
+)
+
+const (
+	defaultAlternativesLimit = 5
+	maxAlternativesLimit     = 50
+	relativeDiffEpsilon      = 1e-9
+)
+
+// Weights used by sizeSimilarity. Resource and price differences are relative,
+// so each contributes between 0 and its weight; the penalties are added when
+// a candidate is a different class or differs in having a GPU.
+const (
+	similarityVcpusWeight  = 0.3
+	similarityMemoryWeight = 0.3
+	similarityDiskWeight   = 0.1
+	similarityPriceWeight  = 0.3
+	similarityClassPenalty = 0.25
+	similarityGPUPenalty   = 1.0
+)
+
+type sizeAlternative struct {
+	godo.Size
+	Score float64 `json:"score"`
+}
+
+type sizeAlternativesResponse struct {
+	Slug         string            `json:"slug"`
+	Region       string            `json:"region,omitempty"`
+	Alternatives []sizeAlternative `json:"alternatives"`
+	RetrievedAt  string            `json:"retrieved_at"`
+}
+
+func (h *handler) sizeAlternatives(w http.ResponseWriter, r *http.Request) {
+	slug := r.PathValue("slug")
+	region := r.URL.Query().Get("region")
+	limit := defaultAlternativesLimit
+	if v := r.URL.Query().Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 || n > maxAlternativesLimit {
+			writeJSONError(w, http.StatusBadRequest)
+			return
+		}
+		limit = n
+	}
+
+	cat, err := h.catalogFor(r.Context(), catalog.ResourceSizes, catalog.ResourceRegions)
+	if err != nil {
+		h.writeUpstreamError(w, r, err)
+		return
+	}
+
+	var target *godo.Size
+	for i := range cat.Sizes {
+		if cat.Sizes[i].Slug == slug {
+			target = &cat.Sizes[i]
+			break
+		}
+	}
+	if target == nil || (region != "" && !regionExists(cat, region)) {
+		writeJSONError(w, http.StatusNotFound)
+		return
+	}
+
+	alternatives := rankAlternatives(cat, *target, region)
+	if len(alternatives) > limit {
+		alternatives = alternatives[:limit]
+	}
+
+	resp := sizeAlternativesResponse{
+		Slug:         slug,
+		Region:       region,
+		Alternatives: alternatives,
+		RetrievedAt:  retrievedAt(cat, catalog.ResourceSizes, catalog.ResourceRegions),
+	}
+
+	writeResponse(w, r, resp)
+}
+
+// rankAlternatives returns the available sizes other than target, optionally
+// limited to those offered in region, ordered from most to least similar.
+// Like recommend, region availability is taken from the region's own list of
+// sizes, so an unavailable region has no alternatives.
+func rankAlternatives(cat *catalog.Catalog, target godo.Size, region string) []sizeAlternative {
+	regionSizes := availableRegionSizes(cat, region)
+	list := []sizeAlternative{}
+	for _, s := range cat.Sizes {
+		if s.Slug == target.Slug || !s.Available {
+			continue
+		}
+		if region != "" && !contains(regionSizes, s.Slug) {
+			continue
+		}
+		list = append(list, sizeAlternative{
+			Size:  s,
+			Score: sizeSimilarity(target, s),
+		})
+	}
+
+	sort.SliceStable(list, func(i, j int) bool {
+		if list[i].Score != list[j].Score {
+			return list[i].Score > list[j].Score
+		}
+		return list[i].PriceMonthly < list[j].PriceMonthly
+	})
+
+	return list
+}
+
+// sizeSimilarity scores how closely candidate matches target, from 1 for an
+// identical size towards 0 for very different ones. It is computed as
+// 1 / (1 + d), where d is the weighted sum of the relative differences in
+// vCPUs, memory, disk and monthly price, plus a penalty if the class
+// (description) differs and a larger one if only one of them has GPUs.
+func sizeSimilarity(target, candidate godo.Size) float64 {
+	d := similarityVcpusWeight*relativeDiff(float64(target.Vcpus), float64(candidate.Vcpus)) +
+		similarityMemoryWeight*relativeDiff(float64(target.Memory), float64(candidate.Memory)) +
+		similarityDiskWeight*relativeDiff(float64(target.Disk), float64(candidate.Disk)) +
+		similarityPriceWeight*relativeDiff(target.PriceMonthly, candidate.PriceMonthly)
+	if target.Description != candidate.Description {
+		d += similarityClassPenalty
+	}
+	if hasGPU(target) != hasGPU(candidate) {
+		d += similarityGPUPenalty
+	}
+
+	score := 1 / (1 + d)
+	// Round so that scores are stable and readable in responses.
+	return math.Round(score*10000) / 10000
+}
+
+// relativeDiff returns |a-b| / max(a, b), which is 0 for equal values and
+// approaches 1 as they diverge.
+func relativeDiff(a, b float64) float64 {
+	m := math.Max(math.Abs(a), math.Abs(b))
+	if m < relativeDiffEpsilon {
+		return 0
+	}
+
+	return math.Abs(a-b) / m
+}
+
+func hasGPU(s godo.Size) bool {
+	return s.GPUInfo != nil && s.GPUInfo.Count > 0
+}
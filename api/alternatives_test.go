@@ -0,0 +1,82 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/andrewsomething/do-api-slugs/api/internal/catalog"
+	"github.com/digitalocean/godo"
+)
+
+func TestSizeSimilarity(t *testing.T) {
+	basic := godo.Size{Slug: "s-1vcpu-1gb", Description: "Basic", Vcpus: 1, Memory: 1024, Disk: 25, PriceMonthly: 6}
+	gpu := basic
+	gpu.GPUInfo = &godo.GPUInfo{Count: 1}
+
+	tests := []struct {
+		name      string
+		candidate godo.Size
+		want      float64
+	}{
+		{name: "identical", candidate: basic, want: 1},
+		{name: "different class", candidate: godo.Size{Description: "General Purpose", Vcpus: 1, Memory: 1024, Disk: 25, PriceMonthly: 6}, want: 0.8},
+		{name: "gpu", candidate: gpu, want: 0.5},
+		{name: "twice the size", candidate: godo.Size{Description: "Basic", Vcpus: 2, Memory: 2048, Disk: 50, PriceMonthly: 12}, want: 0.6667},
+		{name: "twice the price", candidate: godo.Size{Description: "Basic", Vcpus: 1, Memory: 1024, Disk: 25, PriceMonthly: 12}, want: 0.8696},
+		{name: "no disk", candidate: godo.Size{Description: "Basic", Vcpus: 1, Memory: 1024, PriceMonthly: 6}, want: 0.9091},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := sizeSimilarity(basic, tt.candidate); got != tt.want {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	if got := relativeDiff(0, 0); got != 0 {
+		t.Errorf("relativeDiff(0, 0) = %v, want 0", got)
+	}
+}
+
+func TestRankAlternatives(t *testing.T) {
+	target := godo.Size{Slug: "s-2", Description: "Basic", Vcpus: 2, Memory: 2048, PriceMonthly: 12, Available: true, Regions: []string{"nyc1"}}
+	sizes := []godo.Size{
+		target,
+		{Slug: "s-1", Description: "Basic", Vcpus: 1, Memory: 1024, PriceMonthly: 6, Available: true, Regions: []string{"nyc1"}},
+		{Slug: "s-4", Description: "Basic", Vcpus: 4, Memory: 4096, PriceMonthly: 24, Available: true, Regions: []string{"ams3"}},
+		{Slug: "g-2", Description: "General Purpose", Vcpus: 2, Memory: 2048, PriceMonthly: 12, Available: true, Regions: []string{"nyc1"}},
+		{Slug: "g-2b", Description: "General Purpose", Vcpus: 2, Memory: 2048, PriceMonthly: 12, Available: true, Regions: []string{"nyc1"}},
+		{Slug: "s-2-old", Description: "Basic", Vcpus: 2, Memory: 2048, PriceMonthly: 12, Available: false, Regions: []string{"nyc1"}},
+	}
+	// nyc1's own list leaves out g-2b, although the size lists nyc1.
+	cat := &catalog.Catalog{
+		Sizes: sizes,
+		Regions: []godo.Region{
+			{Slug: "nyc1", Available: true, Sizes: []string{"s-1", "s-2", "g-2", "s-2-old"}},
+			{Slug: "ams3", Available: false, Sizes: []string{"s-4"}},
+		},
+	}
+
+	tests := []struct {
+		name   string
+		region string
+		want   []string
+	}{
+		// g-2 and g-2b tie, so they keep their catalog order.
+		{name: "all regions", want: []string{"g-2", "g-2b", "s-1", "s-4"}},
+		{name: "in region", region: "nyc1", want: []string{"g-2", "s-1"}},
+		{name: "unavailable region", region: "ams3", want: []string{}},
+		{name: "unknown region", region: "sfo9", want: []string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := []string{}
+			for _, a := range rankAlternatives(cat, target, tt.region) {
+				got = append(got, a.Slug)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,210 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"sort"
+	"strconv"
+
+	"github.com/andrewsomething/do-api-slugs/api/internal/catalog"
+	"github.com/digitalocean/godo"
+)
+
+const (
+	changeAdded   = "added"
+	changeRemoved = "removed"
+	changeUpdated = "updated"
+)
+
+// change describes a single resource that differs between two catalogs.
+type change struct {
+	Resource string                 `json:"resource"`
+	Slug     string                 `json:"slug"`
+	Action   string                 `json:"action"`
+	Fields   map[string]fieldChange `json:"fields,omitempty"`
+}
+
+// fieldChange holds the JSON encoded before and after values of a field.
+type fieldChange struct {
+	Old json.RawMessage `json:"old"`
+	New json.RawMessage `json:"new"`
+}
+
+// diffCatalogs returns the changes needed to turn prev into cur, ordered by
+// resource and slug. A nil prev has nothing to compare against and yields no
+// changes, and neither does a resource that prev hadn't retrieved yet.
+func diffCatalogs(prev, cur *catalog.Catalog) []change {
+	if prev == nil || cur == nil {
+		return nil
+	}
+
+	var changes []change
+	for _, d := range catalogDiffs {
+		if !retrieved(prev, d.resources...) {
+			continue
+		}
+		changes = append(changes, diffResource(d.name, d.bySlug(prev), d.bySlug(cur))...)
+	}
+
+	return changes
+}
+
+// catalogDiffs lists the kinds of change reported by diffCatalogs, in order,
+// along with the resources each is drawn from.
+var catalogDiffs = []struct {
+	name      string
+	resources []string
+	bySlug    func(*catalog.Catalog) map[string]interface{}
+}{
+	{"size", []string{catalog.ResourceSizes}, sizesBySlug},
+	{"region", []string{catalog.ResourceRegions}, regionsBySlug},
+	{"image", []string{catalog.ResourceAppImages, catalog.ResourceDistroImages}, imagesBySlug},
+	{"k8s_version", []string{catalog.ResourceK8sOptions}, k8sVersionsBySlug},
+	{"app_instance_size", []string{catalog.ResourceAppInstanceSizes}, appInstanceSizesBySlug},
+	{"db_engine", []string{catalog.ResourceDatabaseOptions}, dbEnginesBySlug},
+}
+
+// retrieved reports whether every named resource in cat has been retrieved.
+func retrieved(cat *catalog.Catalog, names ...string) bool {
+	for _, name := range names {
+		if _, ok := cat.ResourceRetrievedAt(name); !ok {
+			return false
+		}
+	}
+
+	return true
+}
+
+func diffResource(resource string, prev, cur map[string]interface{}) []change {
+	var changes []change
+	for slug, v := range cur {
+		old, ok := prev[slug]
+		if !ok {
+			changes = append(changes, change{Resource: resource, Slug: slug, Action: changeAdded})
+			continue
+		}
+		if fields := changedFields(old, v); len(fields) > 0 {
+			changes = append(changes, change{Resource: resource, Slug: slug, Action: changeUpdated, Fields: fields})
+		}
+	}
+	for slug := range prev {
+		if _, ok := cur[slug]; !ok {
+			changes = append(changes, change{Resource: resource, Slug: slug, Action: changeRemoved})
+		}
+	}
+
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Slug < changes[j].Slug
+	})
+
+	return changes
+}
+
+// changedFields compares the JSON representations of two values field by
+// field, so that the reported names match those seen by API consumers.
+func changedFields(old, cur interface{}) map[string]fieldChange {
+	oldFields := jsonFields(old)
+	curFields := jsonFields(cur)
+
+	fields := make(map[string]fieldChange)
+	for name, v := range curFields {
+		if o, ok := oldFields[name]; !ok || !bytes.Equal(o, v) {
+			fields[name] = fieldChange{Old: nullIfEmpty(o), New: v}
+		}
+	}
+	for name, o := range oldFields {
+		if _, ok := curFields[name]; !ok {
+			fields[name] = fieldChange{Old: o, New: json.RawMessage("null")}
+		}
+	}
+
+	return fields
+}
+
+func jsonFields(v interface{}) map[string]json.RawMessage {
+	fields := make(map[string]json.RawMessage)
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fields
+	}
+	json.Unmarshal(b, &fields)
+
+	return fields
+}
+
+func nullIfEmpty(v json.RawMessage) json.RawMessage {
+	if len(v) == 0 {
+		return json.RawMessage("null")
+	}
+
+	return v
+}
+
+func sizesBySlug(c *catalog.Catalog) map[string]interface{} {
+	m := make(map[string]interface{})
+	for _, s := range c.Sizes {
+		m[s.Slug] = s
+	}
+
+	return m
+}
+
+func regionsBySlug(c *catalog.Catalog) map[string]interface{} {
+	m := make(map[string]interface{})
+	for _, r := range c.Regions {
+		m[r.Slug] = r
+	}
+
+	return m
+}
+
+func imagesBySlug(c *catalog.Catalog) map[string]interface{} {
+	m := make(map[string]interface{})
+	for _, images := range [][]godo.Image{c.AppImages, c.DistroImages} {
+		for _, i := range images {
+			m[imageKey(i)] = i
+		}
+	}
+
+	return m
+}
+
+// imageKey identifies an image by slug, falling back to its ID for the rare
+// public images that have none.
+func imageKey(i godo.Image) string {
+	if i.Slug != "" {
+		return i.Slug
+	}
+
+	return strconv.Itoa(i.ID)
+}
+
+func k8sVersionsBySlug(c *catalog.Catalog) map[string]interface{} {
+	m := make(map[string]interface{})
+	if c.K8sOptions == nil {
+		return m
+	}
+	for _, v := range c.K8sOptions.Versions {
+		m[v.Slug] = v
+	}
+
+	return m
+}
+
+func appInstanceSizesBySlug(c *catalog.Catalog) map[string]interface{} {
+	m := make(map[string]interface{})
+	for _, s := range c.AppInstanceSizes {
+		m[s.Slug] = s
+	}
+
+	return m
+}
+
+func dbEnginesBySlug(c *catalog.Catalog) map[string]interface{} {
+	m := make(map[string]interface{})
+	for engine, options := range c.DatabaseOptions {
+		m[engine] = options
+	}
+
+	return m
+}
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/andrewsomething/do-api-slugs/api/internal/catalog"
+	"github.com/digitalocean/godo"
+)
+
+func TestDiffCatalogs(t *testing.T) {
+	base := &catalog.Catalog{
+		Sizes: []godo.Size{
+			{Slug: "s-1", PriceMonthly: 6, Available: true},
+			{Slug: "s-2", PriceMonthly: 12, Available: true},
+		},
+		Regions:      []godo.Region{{Slug: "nyc1", Name: "New York 1"}},
+		DistroImages: []godo.Image{{ID: 42, Name: "Unnamed"}},
+		K8sOptions: &godo.KubernetesOptions{
+			Versions: []*godo.KubernetesVersion{{Slug: "1.30.1-do.0"}},
+		},
+		DatabaseOptions: map[string]interface{}{"pg": map[string]interface{}{"versions": []interface{}{"16"}}},
+		RetrievedAt:     time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+	// sizesOnly has only retrieved sizes, so every other resource in cur
+	// would otherwise look added.
+	sizesOnly := &catalog.Catalog{
+		Sizes:     base.Sizes,
+		Retrieved: map[string]time.Time{catalog.ResourceSizes: base.RetrievedAt},
+	}
+
+	tests := []struct {
+		name string
+		prev *catalog.Catalog
+		cur  *catalog.Catalog
+		want []change
+	}{
+		{
+			name: "first refresh",
+			cur:  base,
+		},
+		{
+			name: "unchanged",
+			prev: base,
+			cur:  base,
+		},
+		{
+			name: "sizes added, removed and updated",
+			prev: base,
+			cur: &catalog.Catalog{
+				Sizes: []godo.Size{
+					{Slug: "s-2", PriceMonthly: 14, Available: true},
+					{Slug: "s-3", PriceMonthly: 24, Available: true},
+				},
+				Regions:         base.Regions,
+				DistroImages:    base.DistroImages,
+				K8sOptions:      base.K8sOptions,
+				DatabaseOptions: base.DatabaseOptions,
+			},
+			want: []change{
+				{Resource: "size", Slug: "s-1", Action: changeRemoved},
+				{Resource: "size", Slug: "s-2", Action: changeUpdated, Fields: map[string]fieldChange{
+					"price_monthly": {Old: json.RawMessage("12"), New: json.RawMessage("14")},
+				}},
+				{Resource: "size", Slug: "s-3", Action: changeAdded},
+			},
+		},
+		{
+			name: "other resources",
+			prev: base,
+			cur: &catalog.Catalog{
+				Sizes:        base.Sizes,
+				Regions:      []godo.Region{{Slug: "nyc1", Name: "New York 1"}, {Slug: "syd1"}},
+				DistroImages: []godo.Image{{ID: 42, Name: "Renamed"}},
+				K8sOptions: &godo.KubernetesOptions{
+					Versions: []*godo.KubernetesVersion{{Slug: "1.31.1-do.0"}},
+				},
+				DatabaseOptions: map[string]interface{}{
+					"pg":    map[string]interface{}{"versions": []interface{}{"16"}},
+					"mysql": map[string]interface{}{},
+				},
+			},
+			want: []change{
+				{Resource: "region", Slug: "syd1", Action: changeAdded},
+				{Resource: "image", Slug: "42", Action: changeUpdated, Fields: map[string]fieldChange{
+					"name": {Old: json.RawMessage(`"Unnamed"`), New: json.RawMessage(`"Renamed"`)},
+				}},
+				{Resource: "k8s_version", Slug: "1.30.1-do.0", Action: changeRemoved},
+				{Resource: "k8s_version", Slug: "1.31.1-do.0", Action: changeAdded},
+				{Resource: "db_engine", Slug: "mysql", Action: changeAdded},
+			},
+		},
+		{
+			name: "resources not retrieved before",
+			prev: sizesOnly,
+			cur:  base,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := diffCatalogs(tt.prev, tt.cur)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
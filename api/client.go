@@ -0,0 +1,208 @@
+package main
+
+import (
+	"context"
+	"math/rand/v2"
+	"net/http"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/digitalocean/godo"
+	"github.com/hashicorp/go-retryablehttp"
+	"golang.org/x/oauth2"
+)
+
+const (
+	defaultUpstreamTimeout = 10 * time.Second
+	defaultRequestTimeout  = 30 * time.Second
+
+	// The defaults match the retry policy of godo.NewFromToken.
+	defaultRetryMax     = 4
+	defaultRetryWaitMin = time.Second
+	defaultRetryWaitMax = 30 * time.Second
+
+	// retryAttemptsHeader is the header godo reads the number of attempts
+	// from when building an ErrorResponse.
+	retryAttemptsHeader  = "X-Godo-Retry-Attempts"
+	rateLimitResetHeader = "RateLimit-Reset"
+)
+
+// retrier decides when calls to the DigitalOcean API are retried and counts
+// the retries made.
+type retrier struct {
+	max     int
+	waitMin time.Duration
+	waitMax time.Duration
+
+	mu        sync.Mutex
+	retries   map[string]int64
+	exhausted int64
+}
+
+// upstreamRetryCount is the number of retries made for one reason, either an
+// HTTP status code or "error" for transport failures.
+type upstreamRetryCount struct {
+	Reason  string `json:"reason"`
+	Retries int64  `json:"retries"`
+}
+
+type upstreamRateLimit struct {
+	Limit     int       `json:"limit"`
+	Remaining int       `json:"remaining"`
+	Reset     time.Time `json:"reset"`
+}
+
+type upstreamStatsResponse struct {
+	Retries          []upstreamRetryCount `json:"retries"`
+	RetriesExhausted int64                `json:"retries_exhausted"`
+	RateLimit        upstreamRateLimit    `json:"rate_limit"`
+	RetrievedAt      string               `json:"retrieved_at"`
+}
+
+func newRetrier(max int, waitMin, waitMax time.Duration) *retrier {
+	return &retrier{
+		max:     max,
+		waitMin: waitMin,
+		waitMax: waitMax,
+		retries: make(map[string]int64),
+	}
+}
+
+// newClient returns a DigitalOcean API client that retries rate limited,
+// failed and 5xx calls according to rt. Each upstream HTTP request, including
+// each retry, is limited to timeout.
+func newClient(token string, timeout time.Duration, rt *retrier) (*godo.Client, error) {
+	retryClient := retryablehttp.NewClient()
+	retryClient.HTTPClient.Timeout = timeout
+	retryClient.Logger = nil
+	retryClient.RetryMax = rt.max
+	retryClient.RetryWaitMin = rt.waitMin
+	retryClient.RetryWaitMax = rt.waitMax
+	retryClient.CheckRetry = rt.checkRetry
+	retryClient.Backoff = rt.backoff
+	retryClient.ErrorHandler = rt.giveUp
+
+	ts := oauth2.StaticTokenSource(&oauth2.Token{AccessToken: strings.Trim(strings.TrimSpace(token), "'")})
+	httpClient := &http.Client{
+		Transport: &upstreamTransport{
+			next: &oauth2.Transport{
+				Base:   &retryablehttp.RoundTripper{Client: retryClient},
+				Source: ts,
+			},
+		},
+	}
+
+	return godo.New(httpClient)
+}
+
+// checkRetry retries the same calls as godo: transport errors, 429s, most
+// 5xx responses and HTTP/2 INTERNAL_ERRORs. Rate limits that won't reset
+// within the maximum wait aren't retried, so the caller can report when to
+// try again instead of timing out.
+func (rt *retrier) checkRetry(ctx context.Context, resp *http.Response, err error) (bool, error) {
+	if err != nil && strings.Contains(err.Error(), "INTERNAL_ERROR") && strings.Contains(reflect.TypeOf(err).String(), "http2") {
+		return true, nil
+	}
+	if wait, ok := rateLimitWait(resp); ok && wait > rt.waitMax {
+		return false, nil
+	}
+
+	return retryablehttp.DefaultRetryPolicy(ctx, resp, err)
+}
+
+// backoff is called before each retry. It waits until a rate limit resets,
+// and otherwise backs off exponentially with jitter so that concurrent
+// callers don't retry in lockstep: half of the wait is fixed and half random.
+func (rt *retrier) backoff(min, max time.Duration, attempt int, resp *http.Response) time.Duration {
+	reason := "error"
+	if resp != nil {
+		reason = strconv.Itoa(resp.StatusCode)
+	}
+	rt.mu.Lock()
+	rt.retries[reason]++
+	rt.mu.Unlock()
+	upstreamRetries.WithLabelValues(reason).Inc()
+
+	if wait, ok := rateLimitWait(resp); ok {
+		return wait
+	}
+
+	wait := max
+	if attempt < 30 && min<<attempt < max {
+		wait = min << attempt
+	}
+
+	return wait/2 + rand.N(wait/2+1)
+}
+
+// giveUp returns the last response once retries are exhausted so godo can
+// build an ErrorResponse from it, as it does for calls that aren't retried.
+func (rt *retrier) giveUp(resp *http.Response, err error, attempts int) (*http.Response, error) {
+	if attempts > rt.max {
+		rt.mu.Lock()
+		rt.exhausted++
+		rt.mu.Unlock()
+		upstreamRetriesExhausted.Inc()
+	}
+	if resp != nil {
+		resp.Header.Add(retryAttemptsHeader, strconv.Itoa(attempts))
+	}
+
+	return resp, err
+}
+
+// stats returns the retry counts, ordered by reason.
+func (rt *retrier) stats() ([]upstreamRetryCount, int64) {
+	rt.mu.Lock()
+	defer rt.mu.Unlock()
+
+	counts := make([]upstreamRetryCount, 0, len(rt.retries))
+	for reason, n := range rt.retries {
+		counts = append(counts, upstreamRetryCount{Reason: reason, Retries: n})
+	}
+	sort.Slice(counts, func(i, j int) bool { return counts[i].Reason < counts[j].Reason })
+
+	return counts, rt.exhausted
+}
+
+// rateLimitWait returns how long to wait before retrying a rate limited
+// response, from its Retry-After header or else the time the rate limit
+// resets.
+func rateLimitWait(resp *http.Response) (time.Duration, bool) {
+	if resp == nil || (resp.StatusCode != http.StatusTooManyRequests && resp.StatusCode != http.StatusServiceUnavailable) {
+		return 0, false
+	}
+	if secs, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && secs >= 0 {
+		return time.Duration(secs) * time.Second, true
+	}
+	if resp.StatusCode == http.StatusTooManyRequests {
+		if reset, err := strconv.ParseInt(resp.Header.Get(rateLimitResetHeader), 10, 64); err == nil {
+			return max(0, time.Until(time.Unix(reset, 0))), true
+		}
+	}
+
+	return 0, false
+}
+
+func (h *handler) upstreamStats(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Cache-Control", "no-store")
+	retries, exhausted := h.retrier.stats()
+	rate := h.client.GetRate()
+	timestamp := time.Now().Format("Mon Jan _2 15:04:05 2006 UTC")
+	resp := upstreamStatsResponse{
+		Retries:          retries,
+		RetriesExhausted: exhausted,
+		RateLimit: upstreamRateLimit{
+			Limit:     rate.Limit,
+			Remaining: rate.Remaining,
+			Reset:     rate.Reset.Time,
+		},
+		RetrievedAt: timestamp,
+	}
+
+	writeResponse(w, r, resp)
+}
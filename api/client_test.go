@@ -0,0 +1,195 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/digitalocean/godo"
+)
+
+func testResponse(status int, headers map[string]string) *http.Response {
+	resp := &http.Response{StatusCode: status, Header: make(http.Header)}
+	for k, v := range headers {
+		resp.Header.Set(k, v)
+	}
+
+	return resp
+}
+
+func TestRateLimitWait(t *testing.T) {
+	reset := strconv.FormatInt(time.Now().Add(time.Minute).Unix(), 10)
+	past := strconv.FormatInt(time.Now().Add(-time.Minute).Unix(), 10)
+
+	tests := []struct {
+		name   string
+		resp   *http.Response
+		want   time.Duration
+		slack  time.Duration
+		wantOK bool
+	}{
+		{name: "no response"},
+		{name: "success", resp: testResponse(http.StatusOK, map[string]string{"Retry-After": "5"})},
+		{name: "server error", resp: testResponse(http.StatusInternalServerError, map[string]string{"Retry-After": "5"})},
+		{name: "retry after", resp: testResponse(http.StatusTooManyRequests, map[string]string{"Retry-After": "5"}), want: 5 * time.Second, wantOK: true},
+		{name: "retry after on 503", resp: testResponse(http.StatusServiceUnavailable, map[string]string{"Retry-After": "2"}), want: 2 * time.Second, wantOK: true},
+		{name: "503 without retry after", resp: testResponse(http.StatusServiceUnavailable, map[string]string{rateLimitResetHeader: reset})},
+		{name: "retry after beats reset", resp: testResponse(http.StatusTooManyRequests, map[string]string{"Retry-After": "1", rateLimitResetHeader: reset}), want: time.Second, wantOK: true},
+		{name: "reset", resp: testResponse(http.StatusTooManyRequests, map[string]string{rateLimitResetHeader: reset}), want: time.Minute, slack: 2 * time.Second, wantOK: true},
+		{name: "reset in the past", resp: testResponse(http.StatusTooManyRequests, map[string]string{rateLimitResetHeader: past}), want: 0, wantOK: true},
+		{name: "invalid retry after", resp: testResponse(http.StatusTooManyRequests, map[string]string{"Retry-After": "-1"})},
+		{name: "no headers", resp: testResponse(http.StatusTooManyRequests, nil)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := rateLimitWait(tt.resp)
+			if ok != tt.wantOK || got < tt.want-tt.slack || got > tt.want {
+				t.Errorf("got %v, %t, want %v (within %v), %t", got, ok, tt.want, tt.slack, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestRetrierBackoff(t *testing.T) {
+	const (
+		waitMin = 100 * time.Millisecond
+		waitMax = time.Second
+	)
+
+	tests := []struct {
+		name    string
+		attempt int
+		resp    *http.Response
+		low     time.Duration
+		high    time.Duration
+	}{
+		{name: "first retry", attempt: 0, low: waitMin / 2, high: waitMin},
+		{name: "second retry", attempt: 1, low: waitMin, high: 2 * waitMin},
+		{name: "third retry", attempt: 2, resp: testResponse(http.StatusBadGateway, nil), low: 2 * waitMin, high: 4 * waitMin},
+		{name: "capped", attempt: 5, low: waitMax / 2, high: waitMax},
+		{name: "no overflow", attempt: 62, low: waitMax / 2, high: waitMax},
+		{name: "rate limited", attempt: 3, resp: testResponse(http.StatusTooManyRequests, map[string]string{"Retry-After": "3"}), low: 3 * time.Second, high: 3 * time.Second},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rt := newRetrier(4, waitMin, waitMax)
+			for range 20 {
+				if got := rt.backoff(waitMin, waitMax, tt.attempt, tt.resp); got < tt.low || got > tt.high {
+					t.Fatalf("got %v, want between %v and %v", got, tt.low, tt.high)
+				}
+			}
+		})
+	}
+}
+
+func TestRetrierCheckRetry(t *testing.T) {
+	rt := newRetrier(4, time.Millisecond, time.Minute)
+	farReset := strconv.FormatInt(time.Now().Add(time.Hour).Unix(), 10)
+
+	tests := []struct {
+		name string
+		resp *http.Response
+		err  error
+		want bool
+	}{
+		{name: "success", resp: testResponse(http.StatusOK, nil)},
+		{name: "not found", resp: testResponse(http.StatusNotFound, nil)},
+		{name: "server error", resp: testResponse(http.StatusInternalServerError, nil), want: true},
+		{name: "not implemented", resp: testResponse(http.StatusNotImplemented, nil)},
+		{name: "rate limited", resp: testResponse(http.StatusTooManyRequests, map[string]string{"Retry-After": "10"}), want: true},
+		{name: "rate limited past the maximum wait", resp: testResponse(http.StatusTooManyRequests, map[string]string{rateLimitResetHeader: farReset})},
+		{name: "transport error", err: errors.New("connection reset by peer"), want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got, _ := rt.checkRetry(context.Background(), tt.resp, tt.err); got != tt.want {
+				t.Errorf("got %t, want %t", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestClientRetries checks the retry policy end to end through godo.
+func TestClientRetries(t *testing.T) {
+	tests := []struct {
+		name      string
+		statuses  []int
+		requests  int32
+		wantErr   int
+		retries   []upstreamRetryCount
+		exhausted int64
+	}{
+		{
+			name:     "retried until success",
+			statuses: []int{http.StatusBadGateway, http.StatusInternalServerError, http.StatusOK},
+			requests: 3,
+			retries:  []upstreamRetryCount{{Reason: "500", Retries: 1}, {Reason: "502", Retries: 1}},
+		},
+		{
+			name:      "retries exhausted",
+			statuses:  []int{http.StatusServiceUnavailable},
+			requests:  3,
+			wantErr:   http.StatusServiceUnavailable,
+			retries:   []upstreamRetryCount{{Reason: "503", Retries: 2}},
+			exhausted: 1,
+		},
+		{
+			name:     "client error",
+			statuses: []int{http.StatusUnauthorized},
+			requests: 1,
+			wantErr:  http.StatusUnauthorized,
+			retries:  []upstreamRetryCount{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var requests atomic.Int32
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				n := requests.Add(1)
+				status := tt.statuses[min(int(n), len(tt.statuses))-1]
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(status)
+				if status == http.StatusOK {
+					w.Write([]byte(`{"sizes":[{"slug":"s-1"}]}`))
+				} else {
+					w.Write([]byte(`{"id":"error","message":"failed"}`))
+				}
+			}))
+			defer srv.Close()
+
+			rt := newRetrier(2, time.Millisecond, 10*time.Millisecond)
+			client, err := newClient("token", time.Second, rt)
+			if err != nil {
+				t.Fatal(err)
+			}
+			client.BaseURL, _ = url.Parse(srv.URL + "/")
+
+			_, _, err = client.Sizes.List(context.Background(), nil)
+			var errResp *godo.ErrorResponse
+			switch {
+			case tt.wantErr == 0 && err != nil:
+				t.Errorf("got error %v, want none", err)
+			case tt.wantErr != 0 && (!errors.As(err, &errResp) || errResp.Response.StatusCode != tt.wantErr):
+				t.Errorf("got error %v, want status %d", err, tt.wantErr)
+			}
+			if n := requests.Load(); n != tt.requests {
+				t.Errorf("got %d requests, want %d", n, tt.requests)
+			}
+			retries, exhausted := rt.stats()
+			if len(retries) != len(tt.retries) || exhausted != tt.exhausted {
+				t.Fatalf("got retries %v and %d exhausted, want %v and %d", retries, exhausted, tt.retries, tt.exhausted)
+			}
+			for i := range retries {
+				if retries[i] != tt.retries[i] {
+					t.Errorf("got retries %v, want %v", retries, tt.retries)
+				}
+			}
+		})
+	}
+}
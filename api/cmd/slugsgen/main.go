@@ -0,0 +1,253 @@
+// Command slugsgen generates a Go package of typed constants and lookup maps
+// for DigitalOcean size, region and image slugs.
+//
+// The catalog is fetched from the DigitalOcean API using DO_TOKEN, or read
+// from a snapshot so that generation can run without network access against
+// a pinned catalog:
+//
+//	//go:generate go run github.com/andrewsomething/do-api-slugs/api/cmd/slugsgen -snapshot catalog.json -out slugs.go
+//
+// A snapshot can be saved while fetching with -save.
+package main
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"fmt"
+	"go/format"
+	"log"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/andrewsomething/do-api-slugs/api/internal/catalog"
+	"github.com/digitalocean/godo"
+)
+
+type constant struct {
+	Ident string
+	Slug  string
+	Info  string
+}
+
+type templateData struct {
+	Package     string
+	RetrievedAt string
+	Sizes       []constant
+	Regions     []constant
+	Images      []constant
+}
+
+var tmpl = template.Must(template.New("slugs").Parse(`// Code generated by slugsgen from the catalog retrieved at {{.RetrievedAt}}. DO NOT EDIT.
+
+// Package {{.Package}} contains DigitalOcean slugs as typed constants.
+package {{.Package}}
+
+// Size is a Droplet size slug.
+type Size string
+
+// Region is a region slug.
+type Region string
+
+// Image is a public image slug.
+type Image string
+
+// SizeInfo describes a Droplet size. Memory is in MB, disk in GB and
+// transfer in TB.
+type SizeInfo struct {
+	Description  string
+	Vcpus        int
+	Memory       int
+	Disk         int
+	Transfer     float64
+	PriceMonthly float64
+	PriceHourly  float64
+	Available    bool
+	Regions      []Region
+}
+
+// RegionInfo describes a region.
+type RegionInfo struct {
+	Name      string
+	Available bool
+	Features  []string
+}
+
+// ImageInfo describes a public image.
+type ImageInfo struct {
+	Name         string
+	Distribution string
+	Status       string
+	Regions      []Region
+}
+
+// Droplet sizes.
+const (
+{{- range .Sizes}}
+	{{.Ident}} Size = {{printf "%q" .Slug}}
+{{- end}}
+)
+
+// Regions.
+const (
+{{- range .Regions}}
+	{{.Ident}} Region = {{printf "%q" .Slug}}
+{{- end}}
+)
+
+// Images.
+const (
+{{- range .Images}}
+	{{.Ident}} Image = {{printf "%q" .Slug}}
+{{- end}}
+)
+
+// Sizes maps each Droplet size to its metadata.
+var Sizes = map[Size]SizeInfo{
+{{- range .Sizes}}
+	{{.Ident}}: {{.Info}},
+{{- end}}
+}
+
+// Regions maps each region to its metadata.
+var Regions = map[Region]RegionInfo{
+{{- range .Regions}}
+	{{.Ident}}: {{.Info}},
+{{- end}}
+}
+
+// Images maps each image to its metadata.
+var Images = map[Image]ImageInfo{
+{{- range .Images}}
+	{{.Ident}}: {{.Info}},
+{{- end}}
+}
+`))
+
+func main() {
+	snapshot := flag.String("snapshot", "", "read the catalog from this snapshot instead of the API")
+	save := flag.String("save", "", "save the fetched catalog as a snapshot at this path")
+	pkg := flag.String("package", "slugs", "name of the generated package")
+	out := flag.String("out", "", "write the generated code to this file instead of stdout")
+	flag.Parse()
+
+	cat, err := loadCatalog(*snapshot, *save)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	src, err := generate(cat, *pkg)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if *out == "" {
+		os.Stdout.Write(src)
+		return
+	}
+	if err := os.WriteFile(*out, src, 0644); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func loadCatalog(snapshot, save string) (*catalog.Catalog, error) {
+	if snapshot != "" {
+		return catalog.Load(snapshot)
+	}
+
+	token := os.Getenv("DO_TOKEN")
+	if token == "" {
+		return nil, fmt.Errorf("DigitalOcean API token not configured; set DO_TOKEN or use -snapshot")
+	}
+	cat, err := catalog.Fetch(context.Background(), godo.NewFromToken(token))
+	if err != nil {
+		return nil, err
+	}
+	if save != "" {
+		if err := catalog.Save(cat, save); err != nil {
+			return nil, err
+		}
+	}
+
+	return cat, nil
+}
+
+func generate(cat *catalog.Catalog, pkg string) ([]byte, error) {
+	data := templateData{
+		Package:     pkg,
+		RetrievedAt: cat.RetrievedAt.Format("Mon Jan _2 15:04:05 2006 UTC"),
+	}
+
+	// Identifiers are assigned in slug order, so the suffixes given to
+	// colliding ones don't depend on the order the API lists slugs in.
+	idents := make(map[string]bool)
+	for _, s := range uniqueBySlug(cat.Sizes, func(s godo.Size) string { return s.Slug }) {
+		data.Sizes = append(data.Sizes, constant{
+			Ident: catalog.UniqueIdentifier(idents, "Size"+catalog.Identifier(s.Slug)),
+			Slug:  s.Slug,
+			Info: fmt.Sprintf("{Description: %q, Vcpus: %d, Memory: %d, Disk: %d, Transfer: %v, PriceMonthly: %v, PriceHourly: %v, Available: %t, Regions: %s}",
+				s.Description, s.Vcpus, s.Memory, s.Disk, s.Transfer, s.PriceMonthly, s.PriceHourly, s.Available, sliceLiteral("[]Region", s.Regions)),
+		})
+	}
+	for _, r := range uniqueBySlug(cat.Regions, func(r godo.Region) string { return r.Slug }) {
+		data.Regions = append(data.Regions, constant{
+			Ident: catalog.UniqueIdentifier(idents, "Region"+catalog.Identifier(r.Slug)),
+			Slug:  r.Slug,
+			Info: fmt.Sprintf("{Name: %q, Available: %t, Features: %s}",
+				r.Name, r.Available, sliceLiteral("[]string", r.Features)),
+		})
+	}
+	// Some images are listed as both application and distribution images,
+	// but each slug can only be a key of Images once.
+	images := append(append([]godo.Image(nil), cat.AppImages...), cat.DistroImages...)
+	for _, i := range uniqueBySlug(images, func(i godo.Image) string { return i.Slug }) {
+		data.Images = append(data.Images, constant{
+			Ident: catalog.UniqueIdentifier(idents, "Image"+catalog.Identifier(i.Slug)),
+			Slug:  i.Slug,
+			Info: fmt.Sprintf("{Name: %q, Distribution: %q, Status: %q, Regions: %s}",
+				i.Name, i.Distribution, i.Status, sliceLiteral("[]Region", i.Regions)),
+		})
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return nil, err
+	}
+
+	return format.Source(buf.Bytes())
+}
+
+// uniqueBySlug returns the items with a slug sorted by slug, keeping only the
+// first of any that share one.
+func uniqueBySlug[T any](items []T, slug func(T) string) []T {
+	sorted := make([]T, 0, len(items))
+	for _, item := range items {
+		if slug(item) != "" {
+			sorted = append(sorted, item)
+		}
+	}
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return slug(sorted[i]) < slug(sorted[j])
+	})
+
+	list := sorted[:0]
+	for i, item := range sorted {
+		if i == 0 || slug(item) != slug(sorted[i-1]) {
+			list = append(list, item)
+		}
+	}
+
+	return list
+}
+
+func sliceLiteral(typ string, values []string) string {
+	quoted := make([]string, 0, len(values))
+	for _, v := range values {
+		quoted = append(quoted, strconv.Quote(v))
+	}
+
+	return typ + "{" + strings.Join(quoted, ", ") + "}"
+}
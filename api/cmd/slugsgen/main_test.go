@@ -0,0 +1,80 @@
+package main
+
+import (
+	"bytes"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"strings"
+	"testing"
+
+	"github.com/andrewsomething/do-api-slugs/api/internal/catalog"
+	"github.com/digitalocean/godo"
+)
+
+func TestGenerateIsStable(t *testing.T) {
+	sizes := []godo.Size{{Slug: "a-b"}, {Slug: "a_b"}, {Slug: "a.b"}}
+	regions := []godo.Region{{Slug: "nyc1"}, {Slug: "ams3"}}
+	forward := &catalog.Catalog{
+		Sizes:        sizes,
+		Regions:      regions,
+		AppImages:    []godo.Image{{Slug: "wordpress"}, {Slug: "ubuntu-24-04-x64", Name: "App"}},
+		DistroImages: []godo.Image{{Slug: "ubuntu-24-04-x64", Name: "Distro"}, {Slug: "debian-12-x64"}},
+	}
+	reversed := &catalog.Catalog{
+		Sizes:        []godo.Size{sizes[2], sizes[1], sizes[0]},
+		Regions:      []godo.Region{regions[1], regions[0]},
+		AppImages:    []godo.Image{{Slug: "ubuntu-24-04-x64", Name: "App"}, {Slug: "wordpress"}},
+		DistroImages: []godo.Image{{Slug: "debian-12-x64"}, {Slug: "ubuntu-24-04-x64", Name: "Distro"}},
+	}
+
+	a, err := generate(forward, "slugs")
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := generate(reversed, "slugs")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(a, b) {
+		t.Errorf("output depends on the order of the catalog:\n%s\n---\n%s", a, b)
+	}
+	// Declarations are aligned by gofmt, so compare without the padding.
+	unpadded := strings.Join(strings.Fields(string(a)), " ")
+	for _, want := range []string{
+		`SizeAB Size = "a-b"`,
+		`SizeAB2 Size = "a.b"`,
+		`SizeAB3 Size = "a_b"`,
+	} {
+		if !strings.Contains(unpadded, want) {
+			t.Errorf("output is missing %s", want)
+		}
+	}
+
+	// Each map literal must have distinct keys for the package to compile.
+	f, err := parser.ParseFile(token.NewFileSet(), "slugs.go", a, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ast.Inspect(f, func(n ast.Node) bool {
+		lit, ok := n.(*ast.CompositeLit)
+		if !ok {
+			return true
+		}
+		if _, ok := lit.Type.(*ast.MapType); !ok {
+			return true
+		}
+		keys := make(map[string]bool)
+		for _, elt := range lit.Elts {
+			key := elt.(*ast.KeyValueExpr).Key.(*ast.Ident).Name
+			if keys[key] {
+				t.Errorf("duplicate map key %s", key)
+			}
+			keys[key] = true
+		}
+		return false
+	})
+	if bytes.Contains(a, []byte(`"Distro"`)) {
+		t.Error("duplicate image slug kept its distribution entry, want the first listed")
+	}
+}
@@ -0,0 +1,119 @@
+package main
+
+import (
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/andrewsomething/do-api-slugs/api/internal/catalog"
+	"github.com/digitalocean/godo"
+)
+
+// databaseSize is a node size usable by managed databases, along with the
+// engines and cluster node counts it supports.
+type databaseSize struct {
+	Slug        string   `json:"slug"`
+	Description string   `json:"description"`
+	Engines     []string `json:"engines"`
+	Nodes       []int    `json:"nodes"`
+}
+
+type databaseSizesResponse struct {
+	Sizes       []databaseSize `json:"sizes"`
+	RetrievedAt string         `json:"retrieved_at"`
+}
+
+// databaseClasses describes database sizes not listed as Droplet sizes,
+// based on their slug prefix. More specific prefixes must come first.
+var databaseClasses = []struct {
+	prefix string
+	class  string
+}{
+	{"db-", "Database"},
+	{"gd-", "General Purpose"},
+	{"m3-", "Memory Optimized"},
+	{"m-", "Memory Optimized"},
+	{"so1_5-", "Storage Optimized"},
+	{"so-", "Storage Optimized"},
+}
+
+func (h *handler) databaseSizes(w http.ResponseWriter, r *http.Request) {
+	cat, err := h.catalogFor(r.Context(), catalog.ResourceDatabaseOptions, catalog.ResourceSizes)
+	if err != nil {
+		h.writeUpstreamError(w, r, err)
+		return
+	}
+	resp := databaseSizesResponse{
+		Sizes:       getDatabaseSizes(cat.DatabaseOptions, cat.Sizes),
+		RetrievedAt: retrievedAt(cat, catalog.ResourceDatabaseOptions, catalog.ResourceSizes),
+	}
+
+	writeResponse(w, r, resp)
+}
+
+// getDatabaseSizes collects the sizes listed in each engine's layouts. Sizes
+// are described using the matching Droplet size where there is one.
+func getDatabaseSizes(options map[string]interface{}, sizes []godo.Size) []databaseSize {
+	descriptions := make(map[string]string)
+	for _, s := range sizes {
+		descriptions[s.Slug] = s.Description
+	}
+
+	bySlug := make(map[string]*databaseSize)
+	for engine, v := range options {
+		engineOptions, _ := v.(map[string]interface{})
+		layouts, _ := engineOptions["layouts"].([]interface{})
+		for _, l := range layouts {
+			layout, _ := l.(map[string]interface{})
+			nodes, _ := layout["num_nodes"].(float64)
+			for _, slug := range stringSlice(layout["sizes"]) {
+				s, ok := bySlug[slug]
+				if !ok {
+					s = &databaseSize{Slug: slug, Description: descriptions[slug]}
+					if s.Description == "" {
+						s.Description = databaseClass(slug)
+					}
+					bySlug[slug] = s
+				}
+				if !contains(s.Engines, engine) {
+					s.Engines = append(s.Engines, engine)
+				}
+				if !containsInt(s.Nodes, int(nodes)) {
+					s.Nodes = append(s.Nodes, int(nodes))
+				}
+			}
+		}
+	}
+
+	list := make([]databaseSize, 0, len(bySlug))
+	for _, s := range bySlug {
+		sort.Strings(s.Engines)
+		sort.Ints(s.Nodes)
+		list = append(list, *s)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].Slug < list[j].Slug
+	})
+
+	return list
+}
+
+func databaseClass(slug string) string {
+	for _, c := range databaseClasses {
+		if strings.HasPrefix(slug, c.prefix) {
+			return c.class
+		}
+	}
+
+	return "Unknown"
+}
+
+func containsInt(list []int, n int) bool {
+	for _, v := range list {
+		if v == n {
+			return true
+		}
+	}
+
+	return false
+}
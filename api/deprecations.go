@@ -0,0 +1,470 @@
+package main
+
+import (
+	"maps"
+	"net/http"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/andrewsomething/do-api-slugs/api/internal/catalog"
+	"github.com/digitalocean/godo"
+)
+
+const (
+	reasonUnavailable        = "unavailable"
+	reasonRemoved            = "removed"
+	reasonRemovedFromRegions = "removed_from_regions"
+	imageStatusAvailable     = "available"
+)
+
+// sizeDeprecation describes a size that is unavailable or has been removed
+// from some or all of its regions. RemovedAt is when the removal was first
+// seen, or the earliest of them for sizes removed from several regions.
+type sizeDeprecation struct {
+	Slug           string     `json:"slug"`
+	Reason         string     `json:"reason"`
+	RemovedRegions []string   `json:"removed_regions,omitempty"`
+	RemovedAt      *time.Time `json:"removed_at,omitempty"`
+	Replacement    string     `json:"replacement,omitempty"`
+}
+
+type imageDeprecation struct {
+	Slug        string `json:"slug"`
+	Status      string `json:"status"`
+	Replacement string `json:"replacement,omitempty"`
+}
+
+type k8sVersionDeprecation struct {
+	Slug        string    `json:"slug"`
+	Reason      string    `json:"reason"`
+	RemovedAt   time.Time `json:"removed_at"`
+	Replacement string    `json:"replacement,omitempty"`
+}
+
+type deprecationsResponse struct {
+	Sizes       []sizeDeprecation       `json:"sizes"`
+	Images      []imageDeprecation      `json:"images"`
+	K8sVersions []k8sVersionDeprecation `json:"k8s_versions"`
+	RetrievedAt string                  `json:"retrieved_at"`
+}
+
+// removedSize is a size that was first seen missing from the catalog, or
+// from some of its regions, at the recorded times.
+type removedSize struct {
+	Size godo.Size `json:"size"`
+	// RemovedAt is set if the size has been removed altogether.
+	RemovedAt *time.Time `json:"removed_at,omitempty"`
+	// Regions holds the regions the size has been removed from, keyed by
+	// region slug, while the size itself is still offered.
+	Regions map[string]time.Time `json:"regions,omitempty"`
+}
+
+type removedK8sVersion struct {
+	Version   *godo.KubernetesVersion `json:"version"`
+	RemovedAt time.Time               `json:"removed_at"`
+}
+
+// removalState is the record of removals as saved in a snapshot.
+type removalState struct {
+	Sizes       map[string]*removedSize       `json:"sizes"`
+	K8sVersions map[string]*removedK8sVersion `json:"k8s_versions"`
+}
+
+// removalTracker keeps a record of the sizes, size regions and Kubernetes
+// versions removed from the catalog, with the time each was first seen
+// missing. Removals stay listed until the slug reappears, rather than only
+// until the next refresh.
+type removalTracker struct {
+	mu    sync.RWMutex
+	state removalState
+}
+
+func newRemovalTracker() *removalTracker {
+	return &removalTracker{
+		state: removalState{
+			Sizes:       make(map[string]*removedSize),
+			K8sVersions: make(map[string]*removedK8sVersion),
+		},
+	}
+}
+
+// record updates the record with the differences between event.Previous and
+// event.Current. Slugs that have reappeared are dropped from it.
+func (t *removalTracker) record(event refreshEvent) {
+	cur := event.Current
+	at := cur.RetrievedAt
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	current := make(map[string]godo.Size)
+	for _, s := range cur.Sizes {
+		current[s.Slug] = s
+	}
+	for slug, removed := range t.state.Sizes {
+		s, ok := current[slug]
+		if !ok {
+			continue
+		}
+		removed.RemovedAt = nil
+		removed.Size = s
+		for region := range removed.Regions {
+			if slices.Contains(s.Regions, region) {
+				delete(removed.Regions, region)
+			}
+		}
+		if len(removed.Regions) == 0 {
+			delete(t.state.Sizes, slug)
+		}
+	}
+	if prev := event.Previous; prev != nil {
+		for _, old := range prev.Sizes {
+			s, ok := current[old.Slug]
+			if !ok {
+				removed := t.removedSize(old)
+				if removed.RemovedAt == nil {
+					removed.RemovedAt = &at
+				}
+				continue
+			}
+			for _, region := range missing(old.Regions, s.Regions) {
+				removed := t.removedSize(s)
+				if _, ok := removed.Regions[region]; !ok {
+					removed.Regions[region] = at
+				}
+			}
+		}
+	}
+
+	currentVersions := make(map[string]bool)
+	if cur.K8sOptions != nil {
+		for _, v := range cur.K8sOptions.Versions {
+			currentVersions[v.Slug] = true
+			delete(t.state.K8sVersions, v.Slug)
+		}
+	}
+	// A catalog without Kubernetes options says nothing about which
+	// versions were removed.
+	if prev := event.Previous; prev != nil && prev.K8sOptions != nil && cur.K8sOptions != nil {
+		for _, v := range prev.K8sOptions.Versions {
+			if currentVersions[v.Slug] {
+				continue
+			}
+			if _, ok := t.state.K8sVersions[v.Slug]; !ok {
+				t.state.K8sVersions[v.Slug] = &removedK8sVersion{Version: v, RemovedAt: at}
+			}
+		}
+	}
+}
+
+// removedSize returns the record for s, creating it if need be. t.mu must be
+// held.
+func (t *removalTracker) removedSize(s godo.Size) *removedSize {
+	removed, ok := t.state.Sizes[s.Slug]
+	if !ok {
+		removed = &removedSize{Size: s, Regions: make(map[string]time.Time)}
+		t.state.Sizes[s.Slug] = removed
+	}
+	if removed.Regions == nil {
+		removed.Regions = make(map[string]time.Time)
+	}
+
+	return removed
+}
+
+// snapshot returns a copy of the record for saving.
+func (t *removalTracker) snapshot() *removalState {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	state := &removalState{
+		Sizes:       make(map[string]*removedSize, len(t.state.Sizes)),
+		K8sVersions: make(map[string]*removedK8sVersion, len(t.state.K8sVersions)),
+	}
+	for slug, removed := range t.state.Sizes {
+		c := *removed
+		c.Regions = maps.Clone(removed.Regions)
+		state.Sizes[slug] = &c
+	}
+	for slug, removed := range t.state.K8sVersions {
+		c := *removed
+		state.K8sVersions[slug] = &c
+	}
+
+	return state
+}
+
+// restore replaces the record with one loaded from a snapshot. It must be
+// called before the first refresh is recorded.
+func (t *removalTracker) restore(state *removalState) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if state.Sizes != nil {
+		t.state.Sizes = state.Sizes
+	}
+	if state.K8sVersions != nil {
+		t.state.K8sVersions = state.K8sVersions
+	}
+}
+
+func (h *handler) deprecations(w http.ResponseWriter, r *http.Request) {
+	cur, err := h.catalogFor(r.Context(), catalog.ResourceSizes, catalog.ResourceAppImages, catalog.ResourceDistroImages, catalog.ResourceK8sOptions)
+	if err != nil {
+		h.writeUpstreamError(w, r, err)
+		return
+	}
+	removals := h.removals.snapshot()
+
+	timestamp := time.Now().Format("Mon Jan _2 15:04:05 2006 UTC")
+	resp := deprecationsResponse{
+		Sizes:       sizeDeprecations(removals, cur),
+		Images:      imageDeprecations(cur),
+		K8sVersions: k8sVersionDeprecations(removals, cur),
+		RetrievedAt: timestamp,
+	}
+
+	writeResponse(w, r, resp)
+}
+
+// sizeDeprecations lists sizes that are unavailable, and sizes that have been
+// removed from some or all of their regions.
+func sizeDeprecations(removals *removalState, cur *catalog.Catalog) []sizeDeprecation {
+	list := []sizeDeprecation{}
+	for _, s := range cur.Sizes {
+		if !s.Available {
+			list = append(list, sizeDeprecation{
+				Slug:        s.Slug,
+				Reason:      reasonUnavailable,
+				Replacement: sizeReplacement(cur, s),
+			})
+		}
+	}
+
+	for slug, removed := range removals.Sizes {
+		if removed.RemovedAt != nil {
+			list = append(list, sizeDeprecation{
+				Slug:           slug,
+				Reason:         reasonRemoved,
+				RemovedRegions: removed.Size.Regions,
+				RemovedAt:      removed.RemovedAt,
+				Replacement:    sizeReplacement(cur, removed.Size),
+			})
+			continue
+		}
+		// Unavailable sizes are already listed.
+		if len(removed.Regions) == 0 || !removed.Size.Available {
+			continue
+		}
+		var regions []string
+		var first time.Time
+		for region, at := range removed.Regions {
+			regions = append(regions, region)
+			if first.IsZero() || at.Before(first) {
+				first = at
+			}
+		}
+		sort.Strings(regions)
+		list = append(list, sizeDeprecation{
+			Slug:           slug,
+			Reason:         reasonRemovedFromRegions,
+			RemovedRegions: regions,
+			RemovedAt:      &first,
+			Replacement:    sizeReplacement(cur, removed.Size),
+		})
+	}
+
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].Slug < list[j].Slug
+	})
+
+	return list
+}
+
+// sizeReplacement suggests an available size of the same class (description)
+// with at least as many vCPUs and as much memory as s. Sizes matching s
+// exactly are preferred, followed by the cheapest. The API doesn't say which
+// generation a size belongs to, so this stands in for "the next generation":
+// an older size is usually replaced by one of the same class and shape.
+func sizeReplacement(cat *catalog.Catalog, s godo.Size) string {
+	var best *godo.Size
+	for i := range cat.Sizes {
+		c := &cat.Sizes[i]
+		if !c.Available || c.Slug == s.Slug || c.Description != s.Description ||
+			c.Vcpus < s.Vcpus || c.Memory < s.Memory {
+			continue
+		}
+		if best == nil || betterReplacement(s, c, best) {
+			best = c
+		}
+	}
+	if best == nil {
+		return ""
+	}
+
+	return best.Slug
+}
+
+func betterReplacement(s godo.Size, a, b *godo.Size) bool {
+	aExact := a.Vcpus == s.Vcpus && a.Memory == s.Memory
+	bExact := b.Vcpus == s.Vcpus && b.Memory == s.Memory
+	if aExact != bExact {
+		return aExact
+	}
+
+	return a.PriceMonthly < b.PriceMonthly
+}
+
+// imageDeprecations lists images whose status is anything other than
+// available, suggesting the newest available image of the same distribution
+// and type.
+func imageDeprecations(cur *catalog.Catalog) []imageDeprecation {
+	list := []imageDeprecation{}
+	for _, images := range [][]godo.Image{cur.AppImages, cur.DistroImages} {
+		for _, i := range images {
+			if i.Status == "" || i.Status == imageStatusAvailable {
+				continue
+			}
+			list = append(list, imageDeprecation{
+				Slug:        imageKey(i),
+				Status:      i.Status,
+				Replacement: imageReplacement(images, i),
+			})
+		}
+	}
+
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].Slug < list[j].Slug
+	})
+
+	return list
+}
+
+func imageReplacement(images []godo.Image, image godo.Image) string {
+	var best *godo.Image
+	for i := range images {
+		c := &images[i]
+		if c.Slug == "" || c.Slug == image.Slug || c.Distribution != image.Distribution ||
+			c.Status != imageStatusAvailable {
+			continue
+		}
+		// created_at is RFC 3339, so it sorts chronologically as a string.
+		if best == nil || c.Created > best.Created {
+			best = c
+		}
+	}
+	if best == nil {
+		return ""
+	}
+
+	return best.Slug
+}
+
+// k8sVersionDeprecations lists the Kubernetes versions that have been removed
+// from the catalog. The suggested replacement is the newest current release
+// of the same minor version or, failing that, the oldest newer release.
+func k8sVersionDeprecations(removals *removalState, cur *catalog.Catalog) []k8sVersionDeprecation {
+	list := []k8sVersionDeprecation{}
+	var versions []*godo.KubernetesVersion
+	if cur.K8sOptions != nil {
+		versions = cur.K8sOptions.Versions
+	}
+	for slug, removed := range removals.K8sVersions {
+		d := k8sVersionDeprecation{
+			Slug:      slug,
+			Reason:    reasonRemoved,
+			RemovedAt: removed.RemovedAt,
+		}
+		if removed.Version != nil {
+			d.Replacement = k8sVersionReplacement(versions, removed.Version)
+		}
+		list = append(list, d)
+	}
+
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].Slug < list[j].Slug
+	})
+
+	return list
+}
+
+func k8sVersionReplacement(versions []*godo.KubernetesVersion, removed *godo.KubernetesVersion) string {
+	minor := minorVersion(removed.KubernetesVersion)
+
+	var sameMinor, newer *godo.KubernetesVersion
+	for _, v := range versions {
+		if minorVersion(v.KubernetesVersion) == minor {
+			if sameMinor == nil || compareVersions(v.KubernetesVersion, sameMinor.KubernetesVersion) > 0 {
+				sameMinor = v
+			}
+			continue
+		}
+		if compareVersions(v.KubernetesVersion, removed.KubernetesVersion) > 0 {
+			if newer == nil || compareVersions(v.KubernetesVersion, newer.KubernetesVersion) < 0 {
+				newer = v
+			}
+		}
+	}
+
+	switch {
+	case sameMinor != nil:
+		return sameMinor.Slug
+	case newer != nil:
+		return newer.Slug
+	}
+
+	return ""
+}
+
+// minorVersion returns the "major.minor" prefix of a version such as "1.29.1".
+func minorVersion(v string) string {
+	parts := strings.SplitN(v, ".", 3)
+	if len(parts) < 2 {
+		return v
+	}
+
+	return parts[0] + "." + parts[1]
+}
+
+// compareVersions compares dotted numeric versions, returning -1, 0 or 1.
+// Non-numeric components compare as zero.
+func compareVersions(a, b string) int {
+	as := strings.Split(a, ".")
+	bs := strings.Split(b, ".")
+	for i := 0; i < len(as) || i < len(bs); i++ {
+		var x, y int
+		if i < len(as) {
+			x, _ = strconv.Atoi(as[i])
+		}
+		if i < len(bs) {
+			y, _ = strconv.Atoi(bs[i])
+		}
+		if x != y {
+			if x < y {
+				return -1
+			}
+			return 1
+		}
+	}
+
+	return 0
+}
+
+// missing returns the elements of a not present in b.
+func missing(a, b []string) []string {
+	present := make(map[string]bool)
+	for _, s := range b {
+		present[s] = true
+	}
+
+	var list []string
+	for _, s := range a {
+		if !present[s] {
+			list = append(list, s)
+		}
+	}
+
+	return list
+}
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/andrewsomething/do-api-slugs/api/internal/catalog"
+	"github.com/digitalocean/godo"
+)
+
+func deprecationCatalog(at time.Time, sizes []godo.Size, versions ...string) *catalog.Catalog {
+	c := &catalog.Catalog{Sizes: sizes, RetrievedAt: at, K8sOptions: &godo.KubernetesOptions{}}
+	for _, v := range versions {
+		c.K8sOptions.Versions = append(c.K8sOptions.Versions, &godo.KubernetesVersion{
+			Slug:              v + "-do.0",
+			KubernetesVersion: v,
+		})
+	}
+
+	return c
+}
+
+func TestDeprecationsPersistRemovals(t *testing.T) {
+	t0 := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	t1 := t0.Add(15 * time.Minute)
+	t2 := t1.Add(15 * time.Minute)
+	t3 := t2.Add(15 * time.Minute)
+
+	s1 := godo.Size{Slug: "s-1", Description: "Basic", Vcpus: 1, Memory: 1024, PriceMonthly: 6, Available: true, Regions: []string{"nyc1", "ams3"}}
+	s1NoAms := s1
+	s1NoAms.Regions = []string{"nyc1"}
+	s2 := godo.Size{Slug: "s-2", Description: "Basic", Vcpus: 1, Memory: 1024, PriceMonthly: 7, Available: true, Regions: []string{"nyc1"}}
+	old := godo.Size{Slug: "old", Description: "Basic", Vcpus: 1, Memory: 1024, PriceMonthly: 5, Available: true, Regions: []string{"nyc1"}}
+
+	catalogs := []*catalog.Catalog{
+		deprecationCatalog(t0, []godo.Size{s1, s2, old}, "1.29.1", "1.30.1"),
+		deprecationCatalog(t1, []godo.Size{s1NoAms, s2}, "1.30.1"),
+		deprecationCatalog(t2, []godo.Size{s1NoAms, s2}, "1.30.1"),
+		deprecationCatalog(t3, []godo.Size{s1NoAms, s2}, "1.30.1"),
+	}
+	tracker := newRemovalTracker()
+	var prev *catalog.Catalog
+	for _, cur := range catalogs {
+		tracker.record(refreshEvent{Previous: prev, Current: cur})
+		prev = cur
+	}
+
+	removals := tracker.snapshot()
+	cur := catalogs[len(catalogs)-1]
+
+	sizes := sizeDeprecations(removals, cur)
+	want := map[string]struct {
+		reason      string
+		replacement string
+	}{
+		"old": {reasonRemoved, "s-1"},
+		"s-1": {reasonRemovedFromRegions, "s-2"},
+	}
+	if len(sizes) != len(want) {
+		t.Fatalf("got %d size deprecations, want %d: %+v", len(sizes), len(want), sizes)
+	}
+	for _, d := range sizes {
+		w, ok := want[d.Slug]
+		if !ok {
+			t.Errorf("unexpected deprecation of %s", d.Slug)
+			continue
+		}
+		if d.Reason != w.reason || d.Replacement != w.replacement {
+			t.Errorf("%s: got %s/%s, want %s/%s", d.Slug, d.Reason, d.Replacement, w.reason, w.replacement)
+		}
+		if d.RemovedAt == nil || !d.RemovedAt.Equal(t1) {
+			t.Errorf("%s: removed at %v, want %v", d.Slug, d.RemovedAt, t1)
+		}
+	}
+
+	versions := k8sVersionDeprecations(removals, cur)
+	if len(versions) != 1 || versions[0].Slug != "1.29.1-do.0" || !versions[0].RemovedAt.Equal(t1) ||
+		versions[0].Replacement != "1.30.1-do.0" {
+		t.Errorf("got %+v, want 1.29.1-do.0 removed at %v replaced by 1.30.1-do.0", versions, t1)
+	}
+}
+
+func TestDeprecationsReappearing(t *testing.T) {
+	t0 := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	s1 := godo.Size{Slug: "s-1", Available: true, Regions: []string{"nyc1", "ams3"}}
+	s1NoAms := s1
+	s1NoAms.Regions = []string{"nyc1"}
+
+	catalogs := []*catalog.Catalog{
+		deprecationCatalog(t0, []godo.Size{s1}, "1.29.1"),
+		deprecationCatalog(t0.Add(time.Hour), []godo.Size{s1NoAms}),
+		deprecationCatalog(t0.Add(2*time.Hour), []godo.Size{s1}, "1.29.1"),
+	}
+	tracker := newRemovalTracker()
+	var prev *catalog.Catalog
+	for _, cur := range catalogs {
+		tracker.record(refreshEvent{Previous: prev, Current: cur})
+		prev = cur
+	}
+
+	removals := tracker.snapshot()
+	cur := catalogs[len(catalogs)-1]
+	if got := sizeDeprecations(removals, cur); len(got) != 0 {
+		t.Errorf("got size deprecations %+v, want none", got)
+	}
+	if got := k8sVersionDeprecations(removals, cur); len(got) != 0 {
+		t.Errorf("got k8s deprecations %+v, want none", got)
+	}
+}
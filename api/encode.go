@@ -0,0 +1,107 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// genericValue converts v to the plain maps, slices and scalars it encodes to
+// as JSON, so that other encoders use the same field names as the json
+// struct tags. Integers are kept as int64 rather than becoming float64.
+func genericValue(v interface{}) (interface{}, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+	var generic interface{}
+	if err := dec.Decode(&generic); err != nil {
+		return nil, err
+	}
+
+	return normalizeNumbers(generic), nil
+}
+
+func normalizeNumbers(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for k, e := range v {
+			v[k] = normalizeNumbers(e)
+		}
+	case []interface{}:
+		for i, e := range v {
+			v[i] = normalizeNumbers(e)
+		}
+	case json.Number:
+		if n, err := v.Int64(); err == nil {
+			return n
+		}
+		f, _ := v.Float64()
+		return f
+	}
+
+	return v
+}
+
+// dropNulls removes null values, which TOML has no way to represent.
+func dropNulls(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for k, e := range v {
+			if e == nil {
+				delete(v, k)
+				continue
+			}
+			v[k] = dropNulls(e)
+		}
+	case []interface{}:
+		list := v[:0]
+		for _, e := range v {
+			if e != nil {
+				list = append(list, dropNulls(e))
+			}
+		}
+		return list
+	}
+
+	return v
+}
+
+func writeYAMLResponse(w http.ResponseWriter, v interface{}) {
+	generic, err := genericValue(v)
+	if err != nil {
+		writeJSONError(w, http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/yaml")
+	setCacheControl(w)
+	enc := yaml.NewEncoder(w)
+	enc.SetIndent(2)
+	enc.Encode(generic)
+	enc.Close()
+}
+
+func writeTOMLResponse(w http.ResponseWriter, v interface{}) {
+	generic, err := genericValue(v)
+	if err != nil {
+		writeJSONError(w, http.StatusInternalServerError)
+		return
+	}
+
+	var buf bytes.Buffer
+	if err := toml.NewEncoder(&buf).Encode(dropNulls(generic)); err != nil {
+		writeJSONError(w, http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/toml")
+	setCacheControl(w)
+	w.Write(buf.Bytes())
+}
@@ -0,0 +1,176 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/digitalocean/godo"
+)
+
+const (
+	// defaultRetryAfter is sent when the upstream API is rate limiting us
+	// but the time the limit resets is unknown.
+	defaultRetryAfter = time.Minute
+	// statusClientClosedRequest is recorded, as nginx does, when the client
+	// goes away before the upstream call finishes.
+	statusClientClosedRequest = 499
+)
+
+// upstreamFailure is the way a call to the DigitalOcean API failed. The HTTP
+// and gRPC APIs each map it to their own status codes.
+type upstreamFailure int
+
+const (
+	upstreamFailureUnknown upstreamFailure = iota
+	upstreamFailureCanceled
+	upstreamFailureTimeout
+	upstreamFailureRateLimited
+	upstreamFailureAuth
+	upstreamFailureAPI
+)
+
+// upstreamFailureMessages are the messages reported to clients for each kind
+// of failure.
+var upstreamFailureMessages = map[upstreamFailure]string{
+	upstreamFailureTimeout:     "upstream timed out",
+	upstreamFailureRateLimited: "upstream rate limit exceeded",
+	upstreamFailureAuth:        "upstream auth failed",
+	upstreamFailureAPI:         "upstream request failed",
+}
+
+// errorResponse is the body of every error response. RequestID identifies
+// the request in our logs; UpstreamRequestID is the DigitalOcean API request
+// that failed, if any, for support tickets.
+type errorResponse struct {
+	Error             string `json:"error"`
+	Message           string `json:"message,omitempty"`
+	RequestID         string `json:"request_id,omitempty"`
+	UpstreamRequestID string `json:"upstream_request_id,omitempty"`
+}
+
+func writeJSONError(w http.ResponseWriter, code int) {
+	writeErrorResponse(w, code, errorResponse{})
+}
+
+// writeErrorResponse writes resp with the given status, filling in the error
+// text and request ID. Errors are never cached.
+func writeErrorResponse(w http.ResponseWriter, code int, resp errorResponse) {
+	resp.Error = http.StatusText(code)
+	resp.RequestID = w.Header().Get(requestIDHeader)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(resp)
+}
+
+// writeUpstreamError logs err with r's request ID and writes an error response with a status
+// describing why the call to the DigitalOcean API failed:
+//
+//   - 502 if the API rejected our token, or failed in some other way
+//   - 503 with Retry-After if we are being rate limited
+//   - 504 if the call timed out
+//
+// Anything else is reported as a 500.
+func (h *handler) writeUpstreamError(w http.ResponseWriter, r *http.Request, err error) {
+	// The client has gone away, so there is no one to respond to, but the
+	// status is recorded so the request isn't counted as a success.
+	if failure, _ := classifyUpstreamError(err); failure == upstreamFailureCanceled {
+		w.WriteHeader(statusClientClosedRequest)
+		return
+	}
+
+	code, resp := h.upstreamError(err)
+	loggerFrom(r.Context()).Error("upstream request failed",
+		"error", err,
+		"status", code,
+		"upstream_request_id", resp.UpstreamRequestID,
+	)
+	if code == http.StatusServiceUnavailable {
+		w.Header().Set("Retry-After", strconv.Itoa(h.retryAfterSeconds()))
+	}
+
+	writeErrorResponse(w, code, resp)
+}
+
+// upstreamContext returns the context for upstream calls made on behalf of
+// r. It is done when the client goes away or the request timeout passes.
+func (h *handler) upstreamContext(r *http.Request) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(r.Context(), h.requestTimeout)
+}
+
+// classifyUpstreamError returns the way a call to the DigitalOcean API
+// failed, and the API's request ID if it responded.
+func classifyUpstreamError(err error) (upstreamFailure, string) {
+	if errors.Is(err, context.Canceled) {
+		return upstreamFailureCanceled, ""
+	}
+
+	var errResp *godo.ErrorResponse
+	if errors.As(err, &errResp) {
+		status := 0
+		if errResp.Response != nil {
+			status = errResp.Response.StatusCode
+		}
+		switch status {
+		case http.StatusUnauthorized, http.StatusForbidden:
+			return upstreamFailureAuth, errResp.RequestID
+		case http.StatusTooManyRequests:
+			return upstreamFailureRateLimited, errResp.RequestID
+		case http.StatusGatewayTimeout:
+			return upstreamFailureTimeout, errResp.RequestID
+		default:
+			return upstreamFailureAPI, errResp.RequestID
+		}
+	}
+
+	var netErr net.Error
+	if errors.Is(err, context.DeadlineExceeded) || (errors.As(err, &netErr) && netErr.Timeout()) {
+		return upstreamFailureTimeout, ""
+	}
+
+	return upstreamFailureUnknown, ""
+}
+
+// upstreamError maps err to a status code and error body.
+func (h *handler) upstreamError(err error) (int, errorResponse) {
+	failure, upstreamID := classifyUpstreamError(err)
+	resp := errorResponse{
+		Message:           upstreamFailureMessages[failure],
+		UpstreamRequestID: upstreamID,
+	}
+	switch failure {
+	case upstreamFailureCanceled:
+		return statusClientClosedRequest, resp
+	case upstreamFailureAuth, upstreamFailureAPI:
+		return http.StatusBadGateway, resp
+	case upstreamFailureRateLimited:
+		return http.StatusServiceUnavailable, resp
+	case upstreamFailureTimeout:
+		return http.StatusGatewayTimeout, resp
+	default:
+		return http.StatusInternalServerError, resp
+	}
+}
+
+// retryAfterSeconds returns the number of seconds until the rate limit last
+// reported by the DigitalOcean API resets.
+func (h *handler) retryAfterSeconds() int {
+	wait := defaultRetryAfter
+	if reset := h.client.GetRate().Reset.Time; !reset.IsZero() {
+		wait = time.Until(reset)
+	}
+
+	return max(1, int(math.Ceil(wait.Seconds())))
+}
+
+// retryAfter is retryAfterSeconds as a duration.
+func (h *handler) retryAfter() time.Duration {
+	return time.Duration(h.retryAfterSeconds()) * time.Second
+}
@@ -0,0 +1,166 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/digitalocean/godo"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// timeoutError is a net.Error that timed out.
+type timeoutError struct{}
+
+func (timeoutError) Error() string   { return "i/o timeout" }
+func (timeoutError) Timeout() bool   { return true }
+func (timeoutError) Temporary() bool { return true }
+
+func upstreamErrorResponse(status int) *godo.ErrorResponse {
+	return &godo.ErrorResponse{
+		Response: &http.Response{
+			StatusCode: status,
+			Request:    httptest.NewRequest(http.MethodGet, "https://api.digitalocean.com/v2/sizes", nil),
+		},
+		Message:   "failed",
+		RequestID: "upstream-1",
+	}
+}
+
+func TestUpstreamError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		code int
+		want errorResponse
+	}{
+		{name: "unauthorized", err: upstreamErrorResponse(http.StatusUnauthorized), code: http.StatusBadGateway, want: errorResponse{Message: "upstream auth failed", UpstreamRequestID: "upstream-1"}},
+		{name: "forbidden", err: upstreamErrorResponse(http.StatusForbidden), code: http.StatusBadGateway, want: errorResponse{Message: "upstream auth failed", UpstreamRequestID: "upstream-1"}},
+		{name: "rate limited", err: upstreamErrorResponse(http.StatusTooManyRequests), code: http.StatusServiceUnavailable, want: errorResponse{Message: "upstream rate limit exceeded", UpstreamRequestID: "upstream-1"}},
+		{name: "gateway timeout", err: upstreamErrorResponse(http.StatusGatewayTimeout), code: http.StatusGatewayTimeout, want: errorResponse{Message: "upstream timed out", UpstreamRequestID: "upstream-1"}},
+		{name: "server error", err: upstreamErrorResponse(http.StatusInternalServerError), code: http.StatusBadGateway, want: errorResponse{Message: "upstream request failed", UpstreamRequestID: "upstream-1"}},
+		{name: "wrapped", err: fmt.Errorf("sizes: %w", upstreamErrorResponse(http.StatusTooManyRequests)), code: http.StatusServiceUnavailable, want: errorResponse{Message: "upstream rate limit exceeded", UpstreamRequestID: "upstream-1"}},
+		{name: "deadline exceeded", err: context.DeadlineExceeded, code: http.StatusGatewayTimeout, want: errorResponse{Message: "upstream timed out"}},
+		{name: "network timeout", err: fmt.Errorf("dial: %w", timeoutError{}), code: http.StatusGatewayTimeout, want: errorResponse{Message: "upstream timed out"}},
+		{name: "canceled", err: fmt.Errorf("sizes: %w", context.Canceled), code: statusClientClosedRequest},
+		{name: "other", err: errors.New("boom"), code: http.StatusInternalServerError},
+	}
+	h := &handler{}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, resp := h.upstreamError(tt.err)
+			if code != tt.code || resp != tt.want {
+				t.Errorf("got %d %+v, want %d %+v", code, resp, tt.code, tt.want)
+			}
+		})
+	}
+}
+
+func TestWriteUpstreamError(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		code       int
+		retryAfter string
+	}{
+		{name: "rate limited", err: upstreamErrorResponse(http.StatusTooManyRequests), code: http.StatusServiceUnavailable, retryAfter: "60"},
+		{name: "auth failed", err: upstreamErrorResponse(http.StatusUnauthorized), code: http.StatusBadGateway},
+		{name: "client went away", err: context.Canceled, code: statusClientClosedRequest},
+	}
+	h := &handler{client: godo.NewClient(nil)}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			rec.Header().Set(requestIDHeader, "request-1")
+			h.writeUpstreamError(rec, httptest.NewRequest(http.MethodGet, "/sizes", nil), tt.err)
+
+			if rec.Code != tt.code {
+				t.Errorf("got status %d, want %d", rec.Code, tt.code)
+			}
+			if tt.code == statusClientClosedRequest {
+				if rec.Body.Len() != 0 {
+					t.Errorf("got response %s, want none", rec.Body)
+				}
+				return
+			}
+			if got := rec.Header().Get("Retry-After"); got != tt.retryAfter {
+				t.Errorf("got Retry-After %q, want %q", got, tt.retryAfter)
+			}
+			var resp errorResponse
+			if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+				t.Fatal(err)
+			}
+			if resp.Error != http.StatusText(tt.code) || resp.RequestID != "request-1" || resp.UpstreamRequestID != "upstream-1" {
+				t.Errorf("got %+v", resp)
+			}
+		})
+	}
+}
+
+func TestUpstreamStatus(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		code       codes.Code
+		message    string
+		retryDelay time.Duration
+	}{
+		{name: "unauthorized", err: upstreamErrorResponse(http.StatusUnauthorized), code: codes.Unavailable, message: "upstream auth failed"},
+		{name: "server error", err: upstreamErrorResponse(http.StatusInternalServerError), code: codes.Unavailable, message: "upstream request failed"},
+		{name: "rate limited", err: upstreamErrorResponse(http.StatusTooManyRequests), code: codes.ResourceExhausted, message: "upstream rate limit exceeded", retryDelay: defaultRetryAfter},
+		{name: "gateway timeout", err: upstreamErrorResponse(http.StatusGatewayTimeout), code: codes.DeadlineExceeded, message: "upstream timed out"},
+		{name: "deadline exceeded", err: context.DeadlineExceeded, code: codes.DeadlineExceeded, message: "upstream timed out"},
+		{name: "canceled", err: context.Canceled, code: codes.Canceled, message: "request canceled"},
+		{name: "other", err: errors.New("boom"), code: codes.Internal, message: "internal error"},
+	}
+	s := &grpcServer{h: &handler{client: godo.NewClient(nil)}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			st := status.Convert(s.upstreamStatus(context.Background(), tt.err))
+			if st.Code() != tt.code || st.Message() != tt.message {
+				t.Errorf("got %s %q, want %s %q", st.Code(), st.Message(), tt.code, tt.message)
+			}
+			var retryDelay time.Duration
+			for _, d := range st.Details() {
+				if info, ok := d.(*errdetails.RetryInfo); ok {
+					retryDelay = info.GetRetryDelay().AsDuration()
+				}
+			}
+			if retryDelay != tt.retryDelay {
+				t.Errorf("got retry delay %v, want %v", retryDelay, tt.retryDelay)
+			}
+		})
+	}
+}
+
+// TestUpstreamErrorFromAPI checks that an error returned by the DigitalOcean
+// API reaches the client with its request ID.
+func TestUpstreamErrorFromAPI(t *testing.T) {
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusUnauthorized)
+		w.Write([]byte(`{"id":"unauthorized","message":"Unable to authenticate you","request_id":"upstream-2"}`))
+	}))
+	h := &handler{client: client, refresher: newRefresher(client, time.Minute), requestTimeout: 5 * time.Second}
+
+	rec := httptest.NewRecorder()
+	h.sizes(rec, httptest.NewRequest(http.MethodGet, "/sizes", nil))
+
+	if rec.Code != http.StatusBadGateway {
+		t.Fatalf("got status %d, want %d", rec.Code, http.StatusBadGateway)
+	}
+	var resp errorResponse
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatal(err)
+	}
+	if resp.Message != "upstream auth failed" || resp.UpstreamRequestID != "upstream-2" {
+		t.Errorf("got %+v", resp)
+	}
+}
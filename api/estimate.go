@@ -0,0 +1,199 @@
+package main
+
+import (
+	"encoding/json"
+	"math"
+	"net/http"
+
+	"github.com/andrewsomething/do-api-slugs/api/internal/catalog"
+)
+
+const (
+	maxEstimateBody = 1 << 20
+
+	// databasePricingNote is added to estimates that include databases. The
+	// DigitalOcean API lists the node sizes of database clusters but not
+	// their prices, which differ from the Droplets of the same size.
+	databasePricingNote = "Database pricing isn't available from the DigitalOcean API, so database clusters are not included in the totals."
+)
+
+// estimateComponent is a quantity of a single slug in a bill of materials.
+// Count defaults to 1 when omitted.
+type estimateComponent struct {
+	Slug  string `json:"slug"`
+	Count int    `json:"count"`
+}
+
+// estimateRequest is a bill of materials. Database clusters use Count as
+// their number of nodes.
+type estimateRequest struct {
+	Droplets      []estimateComponent `json:"droplets"`
+	K8sNodePools  []estimateComponent `json:"k8s_node_pools"`
+	Databases     []estimateComponent `json:"databases"`
+	AppComponents []estimateComponent `json:"app_components"`
+}
+
+type estimateLineItem struct {
+	Category          string  `json:"category"`
+	Slug              string  `json:"slug"`
+	Count             int     `json:"count"`
+	UnitPriceMonthly  float64 `json:"unit_price_monthly"`
+	UnitPriceHourly   float64 `json:"unit_price_hourly"`
+	TotalPriceMonthly float64 `json:"total_price_monthly"`
+	TotalPriceHourly  float64 `json:"total_price_hourly"`
+}
+
+type estimateUnknown struct {
+	Category string `json:"category"`
+	Slug     string `json:"slug"`
+	Reason   string `json:"reason"`
+}
+
+type estimateResponse struct {
+	LineItems    []estimateLineItem `json:"line_items"`
+	Unknown      []estimateUnknown  `json:"unknown"`
+	Notes        []string           `json:"notes,omitempty"`
+	PriceMonthly float64            `json:"price_monthly"`
+	PriceHourly  float64            `json:"price_hourly"`
+	RetrievedAt  string             `json:"retrieved_at"`
+}
+
+type unitPrice struct {
+	monthly float64
+	hourly  float64
+}
+
+func (h *handler) estimate(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		writeJSONError(w, http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req estimateRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxEstimateBody)).Decode(&req); err != nil {
+		writeJSONError(w, http.StatusBadRequest)
+		return
+	}
+
+	cat, err := h.catalogFor(r.Context(), estimateResources...)
+	if err != nil {
+		h.writeUpstreamError(w, r, err)
+		return
+	}
+
+	resp, ok := estimateCost(cat, req)
+	if !ok {
+		writeJSONError(w, http.StatusBadRequest)
+		return
+	}
+
+	writeResponse(w, r, resp)
+}
+
+// estimateResources lists the resources an estimate is priced from.
+var estimateResources = []string{
+	catalog.ResourceSizes,
+	catalog.ResourceAppInstanceSizes,
+	catalog.ResourceDatabaseOptions,
+}
+
+// estimateCost prices each component of req. Droplets and Kubernetes nodes
+// are priced from Droplet sizes, App Platform components from app instance
+// sizes. Databases can't be priced, so they are always listed as unknown and
+// the response says so. Slugs without a known price are listed as unknown
+// rather than failing the estimate. It returns false if any count is
+// negative.
+func estimateCost(cat *catalog.Catalog, req estimateRequest) (estimateResponse, bool) {
+	sizePrices := make(map[string]unitPrice)
+	for _, s := range cat.Sizes {
+		sizePrices[s.Slug] = unitPrice{monthly: s.PriceMonthly, hourly: s.PriceHourly}
+	}
+	appPrices := make(map[string]unitPrice)
+	for _, s := range cat.AppInstanceSizes {
+		monthly, hourly, err := appInstanceSizePrices(s.USDPerMonth, s.USDPerSecond)
+		if err != nil {
+			continue
+		}
+		appPrices[s.Slug] = unitPrice{monthly: monthly, hourly: hourly}
+	}
+	dbSizes := make(map[string]bool)
+	for _, v := range cat.DatabaseOptions {
+		options, _ := v.(map[string]interface{})
+		layouts, _ := options["layouts"].([]interface{})
+		for _, l := range layouts {
+			layout, _ := l.(map[string]interface{})
+			for _, slug := range stringSlice(layout["sizes"]) {
+				dbSizes[slug] = true
+			}
+		}
+	}
+
+	resp := estimateResponse{
+		LineItems:   []estimateLineItem{},
+		Unknown:     []estimateUnknown{},
+		RetrievedAt: retrievedAt(cat, estimateResources...),
+	}
+	groups := []struct {
+		category   string
+		components []estimateComponent
+		prices     map[string]unitPrice
+	}{
+		{"droplet", req.Droplets, sizePrices},
+		{"k8s_node_pool", req.K8sNodePools, sizePrices},
+		{"database", req.Databases, nil},
+		{"app_component", req.AppComponents, appPrices},
+	}
+	for _, g := range groups {
+		for _, c := range g.components {
+			if c.Count < 0 {
+				return estimateResponse{}, false
+			}
+			if c.Count == 0 {
+				c.Count = 1
+			}
+
+			price, ok := g.prices[c.Slug]
+			if !ok {
+				reason := "unknown slug"
+				if g.category == "database" {
+					reason = "not a database size"
+					if dbSizes[c.Slug] {
+						reason = "database pricing not available"
+					}
+				}
+				resp.Unknown = append(resp.Unknown, estimateUnknown{
+					Category: g.category,
+					Slug:     c.Slug,
+					Reason:   reason,
+				})
+				continue
+			}
+
+			item := estimateLineItem{
+				Category:          g.category,
+				Slug:              c.Slug,
+				Count:             c.Count,
+				UnitPriceMonthly:  price.monthly,
+				UnitPriceHourly:   price.hourly,
+				TotalPriceMonthly: roundPrice(price.monthly*float64(c.Count), 2),
+				TotalPriceHourly:  roundPrice(price.hourly*float64(c.Count), 5),
+			}
+			resp.LineItems = append(resp.LineItems, item)
+			resp.PriceMonthly += item.TotalPriceMonthly
+			resp.PriceHourly += item.TotalPriceHourly
+		}
+	}
+	if len(req.Databases) > 0 {
+		resp.Notes = append(resp.Notes, databasePricingNote)
+	}
+	resp.PriceMonthly = roundPrice(resp.PriceMonthly, 2)
+	resp.PriceHourly = roundPrice(resp.PriceHourly, 5)
+
+	return resp, true
+}
+
+func roundPrice(v float64, places int) float64 {
+	p := math.Pow(10, float64(places))
+	return math.Round(v*p) / p
+}
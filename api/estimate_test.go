@@ -0,0 +1,80 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/andrewsomething/do-api-slugs/api/internal/catalog"
+	"github.com/digitalocean/godo"
+)
+
+func TestEstimateCost(t *testing.T) {
+	cat := &catalog.Catalog{
+		RetrievedAt: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		Sizes: []godo.Size{
+			{Slug: "s-1vcpu-1gb", PriceMonthly: 6, PriceHourly: 0.00893},
+			{Slug: "s-2vcpu-4gb", PriceMonthly: 24, PriceHourly: 0.03571},
+		},
+		DatabaseOptions: map[string]interface{}{
+			"pg": map[string]interface{}{
+				"layouts": []interface{}{
+					map[string]interface{}{"num_nodes": 1.0, "sizes": []interface{}{"db-s-1vcpu-1gb"}},
+				},
+			},
+		},
+	}
+
+	tests := []struct {
+		name    string
+		req     estimateRequest
+		monthly float64
+		unknown []estimateUnknown
+		notes   []string
+	}{
+		{
+			name:    "droplets and node pools",
+			req:     estimateRequest{Droplets: []estimateComponent{{Slug: "s-1vcpu-1gb", Count: 2}}, K8sNodePools: []estimateComponent{{Slug: "s-2vcpu-4gb"}}},
+			monthly: 36,
+			unknown: []estimateUnknown{},
+		},
+		{
+			name:    "unknown droplet",
+			req:     estimateRequest{Droplets: []estimateComponent{{Slug: "s-64vcpu"}}},
+			unknown: []estimateUnknown{{Category: "droplet", Slug: "s-64vcpu", Reason: "unknown slug"}},
+		},
+		{
+			name:    "database size is not priced",
+			req:     estimateRequest{Databases: []estimateComponent{{Slug: "db-s-1vcpu-1gb", Count: 3}}},
+			unknown: []estimateUnknown{{Category: "database", Slug: "db-s-1vcpu-1gb", Reason: "database pricing not available"}},
+			notes:   []string{databasePricingNote},
+		},
+		{
+			name:    "droplet size is not a database",
+			req:     estimateRequest{Databases: []estimateComponent{{Slug: "s-1vcpu-1gb"}}},
+			unknown: []estimateUnknown{{Category: "database", Slug: "s-1vcpu-1gb", Reason: "not a database size"}},
+			notes:   []string{databasePricingNote},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, ok := estimateCost(cat, tt.req)
+			if !ok {
+				t.Fatal("estimate failed")
+			}
+			if resp.PriceMonthly != tt.monthly {
+				t.Errorf("got monthly price %v, want %v", resp.PriceMonthly, tt.monthly)
+			}
+			if !reflect.DeepEqual(resp.Unknown, tt.unknown) {
+				t.Errorf("got unknown %+v, want %+v", resp.Unknown, tt.unknown)
+			}
+			if !reflect.DeepEqual(resp.Notes, tt.notes) {
+				t.Errorf("got notes %q, want %q", resp.Notes, tt.notes)
+			}
+		})
+	}
+
+	if _, ok := estimateCost(cat, estimateRequest{Droplets: []estimateComponent{{Slug: "s-1vcpu-1gb", Count: -1}}}); ok {
+		t.Error("negative count was accepted")
+	}
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+const (
+	eventsKeepAlive  = 30 * time.Second
+	eventsRetryDelay = 10 * time.Second
+)
+
+// events streams catalog changes as Server-Sent Events. Each refresh that
+// changes the catalog is sent as a catalog.changed event with the same
+// payload as webhooks. Comments are sent periodically so proxies don't close
+// idle streams. The stream is exempt from the server's write timeout and
+// ends when the server shuts down.
+func (h *handler) events(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeJSONError(w, http.StatusInternalServerError)
+		return
+	}
+
+	if err := http.NewResponseController(w).SetWriteDeadline(time.Time{}); err != nil {
+		loggerFrom(r.Context()).Warn("unable to clear write deadline", "error", err)
+	}
+
+	events, unsubscribe := h.refresher.subscribe()
+	defer unsubscribe()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-store")
+	// Disable response buffering in nginx so events are sent immediately.
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	fmt.Fprintf(w, "retry: %d\n\n", eventsRetryDelay.Milliseconds())
+	flusher.Flush()
+
+	keepAlive := time.NewTicker(eventsKeepAlive)
+	defer keepAlive.Stop()
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-h.stopping:
+			return
+		case <-keepAlive.C:
+			fmt.Fprint(w, ": keep-alive\n\n")
+			flusher.Flush()
+		case event := <-events:
+			if len(event.Changes) == 0 {
+				continue
+			}
+			id, err := newID()
+			if err != nil {
+				loggerFrom(r.Context()).Error("unable to send event", "error", err)
+				continue
+			}
+			data, err := json.Marshal(webhookEvent{
+				ID:          id,
+				Type:        webhookEventType,
+				CreatedAt:   time.Now().UTC(),
+				RetrievedAt: event.Current.RetrievedAt,
+				Changes:     event.Changes,
+			})
+			if err != nil {
+				loggerFrom(r.Context()).Error("unable to send event", "error", err)
+				continue
+			}
+			fmt.Fprintf(w, "id: %s\nevent: %s\ndata: %s\n\n", id, webhookEventType, data)
+			flusher.Flush()
+		}
+	}
+}
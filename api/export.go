@@ -0,0 +1,163 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/andrewsomething/do-api-slugs/api/internal/catalog"
+	"github.com/digitalocean/godo"
+)
+
+// allowedSlugs are the slugs offered to infrastructure-as-code exports. Only
+// available resources are included.
+type allowedSlugs struct {
+	Sizes   []string
+	Regions []string
+	Images  []string
+}
+
+// exportVariable describes how one kind of slug is exported.
+type exportVariable struct {
+	name        string
+	enum        string
+	description string
+	slugs       func(allowedSlugs) []string
+}
+
+var exportVariables = []exportVariable{
+	{
+		name:        "size",
+		enum:        "SizeSlug",
+		description: "DigitalOcean Droplet size slug",
+		slugs:       func(a allowedSlugs) []string { return a.Sizes },
+	},
+	{
+		name:        "region",
+		enum:        "RegionSlug",
+		description: "DigitalOcean region slug",
+		slugs:       func(a allowedSlugs) []string { return a.Regions },
+	},
+	{
+		name:        "image",
+		enum:        "ImageSlug",
+		description: "DigitalOcean image slug",
+		slugs:       func(a allowedSlugs) []string { return a.Images },
+	},
+}
+
+// exportResources lists the resources the allowed slugs are drawn from.
+var exportResources = []string{
+	catalog.ResourceSizes,
+	catalog.ResourceRegions,
+	catalog.ResourceAppImages,
+	catalog.ResourceDistroImages,
+}
+
+// allowedSlugsFor collects the available size, region and image slugs. If
+// region is set, only that region and the sizes and images offered in it
+// are included.
+func allowedSlugsFor(cat *catalog.Catalog, region string) allowedSlugs {
+	var a allowedSlugs
+	for _, s := range cat.Sizes {
+		if s.Available && (region == "" || contains(s.Regions, region)) {
+			a.Sizes = append(a.Sizes, s.Slug)
+		}
+	}
+	for _, r := range cat.Regions {
+		if r.Available && (region == "" || r.Slug == region) {
+			a.Regions = append(a.Regions, r.Slug)
+		}
+	}
+	for _, images := range [][]godo.Image{cat.AppImages, cat.DistroImages} {
+		for _, i := range images {
+			if i.Slug == "" || (i.Status != "" && i.Status != imageStatusAvailable) {
+				continue
+			}
+			if region == "" || contains(i.Regions, region) {
+				a.Images = append(a.Images, i.Slug)
+			}
+		}
+	}
+
+	sort.Strings(a.Sizes)
+	sort.Strings(a.Regions)
+	sort.Strings(a.Images)
+
+	return a
+}
+
+// exportCatalog returns the catalog and allowed slugs for an export request,
+// writing an error response and returning false if they can't be found.
+func (h *handler) exportCatalog(w http.ResponseWriter, r *http.Request) (*catalog.Catalog, allowedSlugs, bool) {
+	cat, err := h.catalogFor(r.Context(), exportResources...)
+	if err != nil {
+		h.writeUpstreamError(w, r, err)
+		return nil, allowedSlugs{}, false
+	}
+	region := r.URL.Query().Get("region")
+	if region != "" && !regionExists(cat, region) {
+		writeJSONError(w, http.StatusNotFound)
+		return nil, allowedSlugs{}, false
+	}
+
+	return cat, allowedSlugsFor(cat, region), true
+}
+
+// exportTerraform writes HCL variable blocks whose validation rules only
+// accept the allowed slugs.
+func (h *handler) exportTerraform(w http.ResponseWriter, r *http.Request) {
+	cat, allowed, ok := h.exportCatalog(w, r)
+	if !ok {
+		return
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "# Generated by do-api-slugs from the catalog retrieved at %s.\n", retrievedAt(cat, exportResources...))
+	for _, v := range exportVariables {
+		fmt.Fprintf(&b, "\nvariable %q {\n", v.name)
+		fmt.Fprintf(&b, "  type        = string\n")
+		fmt.Fprintf(&b, "  description = %q\n", v.description)
+		fmt.Fprintf(&b, "\n  validation {\n")
+		fmt.Fprintf(&b, "    condition = contains([\n")
+		for _, slug := range v.slugs(allowed) {
+			fmt.Fprintf(&b, "      %q,\n", slug)
+		}
+		fmt.Fprintf(&b, "    ], var.%s)\n", v.name)
+		fmt.Fprintf(&b, "    error_message = %q\n", "The "+v.name+" must be an available "+v.description+".")
+		fmt.Fprintf(&b, "  }\n}\n")
+	}
+
+	writeTextExport(w, b.String())
+}
+
+// exportPulumi writes TypeScript string enums of the allowed slugs for use
+// with Pulumi programs.
+func (h *handler) exportPulumi(w http.ResponseWriter, r *http.Request) {
+	cat, allowed, ok := h.exportCatalog(w, r)
+	if !ok {
+		return
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "// Generated by do-api-slugs from the catalog retrieved at %s.\n", retrievedAt(cat, exportResources...))
+	for _, v := range exportVariables {
+		fmt.Fprintf(&b, "\n/** %s. */\n", v.description)
+		fmt.Fprintf(&b, "export enum %s {\n", v.enum)
+		used := make(map[string]bool)
+		for _, slug := range v.slugs(allowed) {
+			ident := catalog.UniqueIdentifier(used, catalog.Identifier(slug))
+			fmt.Fprintf(&b, "  %s = %q,\n", ident, slug)
+		}
+		fmt.Fprintf(&b, "}\n")
+	}
+
+	writeTextExport(w, b.String())
+}
+
+func writeTextExport(w http.ResponseWriter, body string) {
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	setCacheControl(w)
+	fmt.Fprint(w, body)
+}
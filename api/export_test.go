@@ -0,0 +1,34 @@
+package main
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/andrewsomething/do-api-slugs/api/internal/catalog"
+	"github.com/digitalocean/godo"
+)
+
+func TestExportPulumiUniqueIdentifiers(t *testing.T) {
+	cat := &catalog.Catalog{
+		RetrievedAt: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		Sizes: []godo.Size{
+			{Slug: "a-b", Available: true},
+			{Slug: "a.b", Available: true},
+			{Slug: "a_b", Available: true},
+		},
+	}
+	h := &handler{refresher: newRefresher(nil, time.Minute)}
+	h.refresher.seed(cat)
+
+	rec := httptest.NewRecorder()
+	h.exportPulumi(rec, httptest.NewRequest("GET", "/export/pulumi", nil))
+
+	body := rec.Body.String()
+	for _, want := range []string{`AB = "a-b",`, `AB2 = "a.b",`, `AB3 = "a_b",`} {
+		if !strings.Contains(body, want) {
+			t.Errorf("export is missing %s:\n%s", want, body)
+		}
+	}
+}
@@ -0,0 +1,241 @@
+package main
+
+import (
+	"encoding/csv"
+	"fmt"
+	"mime"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"unicode/utf8"
+)
+
+const (
+	formatJSON     = "json"
+	formatCSV      = "csv"
+	formatYAML     = "yaml"
+	formatTOML     = "toml"
+	formatMarkdown = "markdown"
+	formatText     = "text"
+)
+
+// mediaTypes maps the media types accepted in an Accept header to formats.
+var mediaTypes = map[string]string{
+	"application/json":   formatJSON,
+	"text/csv":           formatCSV,
+	"application/yaml":   formatYAML,
+	"application/x-yaml": formatYAML,
+	"text/yaml":          formatYAML,
+	"application/toml":   formatTOML,
+	"text/markdown":      formatMarkdown,
+	"text/plain":         formatText,
+}
+
+// table is a flattened, row-oriented view of a response used by formats
+// that can't represent nested values.
+type table struct {
+	Columns []string
+	Rows    [][]string
+}
+
+// tabular is implemented by responses that can be rendered as a table.
+type tabular interface {
+	table() table
+}
+
+// summarizer is implemented by tabular responses to choose the key columns
+// shown by the formats meant for people rather than programs.
+type summarizer interface {
+	keyColumns() []string
+}
+
+// project returns a copy of t containing only the named columns, in the
+// order given. Unknown columns are ignored.
+func (t table) project(columns []string) table {
+	var indexes []int
+	p := table{Rows: make([][]string, 0, len(t.Rows))}
+	for _, c := range columns {
+		for i, name := range t.Columns {
+			if name == c {
+				indexes = append(indexes, i)
+				p.Columns = append(p.Columns, c)
+			}
+		}
+	}
+	for _, row := range t.Rows {
+		projected := make([]string, 0, len(indexes))
+		for _, i := range indexes {
+			projected = append(projected, row[i])
+		}
+		p.Rows = append(p.Rows, projected)
+	}
+
+	return p
+}
+
+// tableFormats are the formats that can only represent tabular responses.
+var tableFormats = map[string]bool{
+	formatCSV:      true,
+	formatMarkdown: true,
+	formatText:     true,
+}
+
+// acceptedType is one media range from an Accept header.
+type acceptedType struct {
+	mediaType string
+	q         float64
+}
+
+// parseAccept returns the media ranges in an Accept header that the client
+// will accept at all, most preferred first. Ranges with equal quality keep
+// the order they were listed in.
+func parseAccept(header string) []acceptedType {
+	var types []acceptedType
+	for _, accept := range strings.Split(header, ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(accept))
+		if err != nil {
+			continue
+		}
+		q := 1.0
+		if v, ok := params["q"]; ok {
+			if q, err = strconv.ParseFloat(v, 64); err != nil {
+				continue
+			}
+		}
+		if q <= 0 {
+			continue
+		}
+		types = append(types, acceptedType{mediaType: mediaType, q: q})
+	}
+	sort.SliceStable(types, func(i, j int) bool { return types[i].q > types[j].q })
+
+	return types
+}
+
+// responseFormat returns the format requested by r, preferring the format
+// query parameter over the Accept header. The table based formats are only
+// chosen if isTabular is set. From the Accept header, the most preferred
+// format that can represent the response is used, or JSON if the client
+// accepts any type or names none that are supported. It returns false if no
+// acceptable format can represent the response.
+func responseFormat(r *http.Request, isTabular bool) (string, bool) {
+	if f := r.URL.Query().Get("format"); f != "" {
+		for _, format := range mediaTypes {
+			if f == format {
+				return f, isTabular || !tableFormats[f]
+			}
+		}
+		return "", false
+	}
+
+	recognised := false
+	for _, accept := range parseAccept(r.Header.Get("Accept")) {
+		switch accept.mediaType {
+		case "*/*", "application/*":
+			return formatJSON, true
+		}
+		if format, ok := mediaTypes[accept.mediaType]; ok {
+			if isTabular || !tableFormats[format] {
+				return format, true
+			}
+			recognised = true
+		}
+	}
+
+	return formatJSON, !recognised
+}
+
+// writeResponse writes v in the format negotiated with the client. The table
+// based formats are only available for responses that implement tabular.
+func writeResponse(w http.ResponseWriter, r *http.Request, v interface{}) {
+	w.Header().Add("Vary", "Accept")
+
+	t, isTabular := v.(tabular)
+	format, ok := responseFormat(r, isTabular)
+	if !ok {
+		writeJSONError(w, http.StatusNotAcceptable)
+		return
+	}
+
+	switch format {
+	case formatCSV:
+		writeCSVResponse(w, t.table())
+	case formatMarkdown, formatText:
+		summary := t.table()
+		if s, ok := v.(summarizer); ok {
+			summary = summary.project(s.keyColumns())
+		}
+		if format == formatMarkdown {
+			writeMarkdownResponse(w, summary)
+		} else {
+			writeTextResponse(w, summary)
+		}
+	case formatYAML:
+		writeYAMLResponse(w, v)
+	case formatTOML:
+		writeTOMLResponse(w, v)
+	default:
+		writeJSONResponse(w, v)
+	}
+}
+
+func writeCSVResponse(w http.ResponseWriter, t table) {
+	w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	setCacheControl(w)
+	cw := csv.NewWriter(w)
+	cw.Write(t.Columns)
+	cw.WriteAll(t.Rows)
+}
+
+// writeMarkdownResponse renders t as a GitHub flavored Markdown table with
+// padded columns, so it reads well both rendered and as plain text.
+func writeMarkdownResponse(w http.ResponseWriter, t table) {
+	w.Header().Set("Content-Type", "text/markdown; charset=utf-8")
+	setCacheControl(w)
+
+	escape := func(s string) string {
+		return strings.ReplaceAll(s, "|", "\\|")
+	}
+	widths := make([]int, len(t.Columns))
+	for i, c := range t.Columns {
+		widths[i] = max(utf8.RuneCountInString(c), 3)
+	}
+	for _, row := range t.Rows {
+		for i, cell := range row {
+			widths[i] = max(widths[i], utf8.RuneCountInString(escape(cell)))
+		}
+	}
+
+	writeRow := func(cells []string) {
+		fmt.Fprint(w, "|")
+		for i, cell := range cells {
+			cell = escape(cell)
+			fmt.Fprintf(w, " %s%s |", cell, strings.Repeat(" ", widths[i]-utf8.RuneCountInString(cell)))
+		}
+		fmt.Fprintln(w)
+	}
+	writeRow(t.Columns)
+	fmt.Fprint(w, "|")
+	for _, width := range widths {
+		fmt.Fprintf(w, " %s |", strings.Repeat("-", width))
+	}
+	fmt.Fprintln(w)
+	for _, row := range t.Rows {
+		writeRow(row)
+	}
+}
+
+// writeTextResponse renders t as aligned columns for terminals.
+func writeTextResponse(w http.ResponseWriter, t table) {
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	setCacheControl(w)
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, strings.ToUpper(strings.Join(t.Columns, "\t")))
+	for _, row := range t.Rows {
+		fmt.Fprintln(tw, strings.Join(row, "\t"))
+	}
+	tw.Flush()
+}
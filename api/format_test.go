@@ -0,0 +1,46 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestResponseFormat(t *testing.T) {
+	tests := []struct {
+		name      string
+		url       string
+		accept    string
+		isTabular bool
+		want      string
+		wantOK    bool
+	}{
+		{name: "default", url: "/sizes", want: formatJSON, wantOK: true},
+		{name: "query parameter", url: "/sizes?format=csv", isTabular: true, want: formatCSV, wantOK: true},
+		{name: "query parameter beats accept", url: "/sizes?format=yaml", accept: "text/csv", want: formatYAML, wantOK: true},
+		{name: "unknown query parameter", url: "/sizes?format=xml", wantOK: false},
+		{name: "query parameter not tabular", url: "/k8s?format=text", wantOK: false},
+		{name: "accept", url: "/sizes", accept: "text/csv", isTabular: true, want: formatCSV, wantOK: true},
+		{name: "accept with parameters", url: "/sizes", accept: "text/markdown; charset=utf-8", isTabular: true, want: formatMarkdown, wantOK: true},
+		{name: "highest quality wins", url: "/sizes", accept: "text/csv;q=0.5, application/yaml", isTabular: true, want: formatYAML, wantOK: true},
+		{name: "equal quality keeps order", url: "/sizes", accept: "text/plain, text/csv", isTabular: true, want: formatText, wantOK: true},
+		{name: "zero quality is refused", url: "/sizes", accept: "application/yaml;q=0, text/csv;q=0.1", isTabular: true, want: formatCSV, wantOK: true},
+		{name: "falls through to next acceptable type", url: "/k8s", accept: "text/plain, application/yaml;q=0.9", want: formatYAML, wantOK: true},
+		{name: "wildcard after table format", url: "/k8s", accept: "text/plain, */*", want: formatJSON, wantOK: true},
+		{name: "application wildcard", url: "/k8s", accept: "text/csv, application/*;q=0.8", want: formatJSON, wantOK: true},
+		{name: "only table formats", url: "/k8s", accept: "text/plain, text/csv", wantOK: false},
+		{name: "unsupported types", url: "/sizes", accept: "text/html", want: formatJSON, wantOK: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, tt.url, nil)
+			if tt.accept != "" {
+				r.Header.Set("Accept", tt.accept)
+			}
+			got, ok := responseFormat(r, tt.isTabular)
+			if ok != tt.wantOK || (ok && got != tt.want) {
+				t.Errorf("got %q, %t, want %q, %t", got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
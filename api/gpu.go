@@ -0,0 +1,113 @@
+package main
+
+import (
+	"net/http"
+	"sort"
+
+	"github.com/andrewsomething/do-api-slugs/api/internal/catalog"
+	"github.com/digitalocean/godo"
+)
+
+const (
+	diskTypeScratch = "scratch"
+)
+
+// gpuSize is a GPU Droplet size with the nested gpu_info and disk_info
+// fields flattened. Units follow godo.Size: memory in MB, boot disk in GB and
+// transfer in TB. Regions lists the available regions offering the size.
+type gpuSize struct {
+	Slug              string   `json:"slug"`
+	Description       string   `json:"description"`
+	GPUModel          string   `json:"gpu_model"`
+	GPUCount          int      `json:"gpu_count"`
+	GPUVRAMAmount     int      `json:"gpu_vram_amount"`
+	GPUVRAMUnit       string   `json:"gpu_vram_unit"`
+	Vcpus             int      `json:"vcpus"`
+	Memory            int      `json:"memory"`
+	BootDisk          int      `json:"boot_disk"`
+	ScratchDiskAmount int      `json:"scratch_disk_amount"`
+	ScratchDiskUnit   string   `json:"scratch_disk_unit"`
+	Transfer          float64  `json:"transfer"`
+	PriceMonthly      float64  `json:"price_monthly"`
+	PriceHourly       float64  `json:"price_hourly"`
+	Available         bool     `json:"available"`
+	Regions           []string `json:"regions"`
+}
+
+type gpuSizesResponse struct {
+	Sizes       []gpuSize `json:"sizes"`
+	RetrievedAt string    `json:"retrieved_at"`
+}
+
+func (h *handler) gpuSizes(w http.ResponseWriter, r *http.Request) {
+	cat, err := h.catalogFor(r.Context(), catalog.ResourceSizes, catalog.ResourceRegions)
+	if err != nil {
+		h.writeUpstreamError(w, r, err)
+		return
+	}
+
+	resp := gpuSizesResponse{
+		Sizes:       flattenGPUSizes(cat),
+		RetrievedAt: retrievedAt(cat, catalog.ResourceSizes, catalog.ResourceRegions),
+	}
+
+	writeResponse(w, r, resp)
+}
+
+func flattenGPUSizes(cat *catalog.Catalog) []gpuSize {
+	regionSizes := make(map[string][]string)
+	for _, r := range cat.Regions {
+		if r.Available {
+			regionSizes[r.Slug] = r.Sizes
+		}
+	}
+
+	list := []gpuSize{}
+	for _, s := range cat.Sizes {
+		if !hasGPU(s) {
+			continue
+		}
+		list = append(list, flattenGPUSize(s, regionSizes))
+	}
+
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].Slug < list[j].Slug
+	})
+
+	return list
+}
+
+func flattenGPUSize(s godo.Size, regionSizes map[string][]string) gpuSize {
+	g := gpuSize{
+		Slug:         s.Slug,
+		Description:  s.Description,
+		GPUModel:     s.GPUInfo.Model,
+		GPUCount:     s.GPUInfo.Count,
+		Vcpus:        s.Vcpus,
+		Memory:       s.Memory,
+		BootDisk:     s.Disk,
+		Transfer:     s.Transfer,
+		PriceMonthly: s.PriceMonthly,
+		PriceHourly:  s.PriceHourly,
+		Available:    s.Available,
+		Regions:      []string{},
+	}
+	if s.GPUInfo.VRAM != nil {
+		g.GPUVRAMAmount = s.GPUInfo.VRAM.Amount
+		g.GPUVRAMUnit = s.GPUInfo.VRAM.Unit
+	}
+	for _, d := range s.DiskInfo {
+		if d.Type == diskTypeScratch && d.Size != nil {
+			g.ScratchDiskAmount = d.Size.Amount
+			g.ScratchDiskUnit = d.Size.Unit
+		}
+	}
+	for _, r := range s.Regions {
+		if contains(regionSizes[r], s.Slug) {
+			g.Regions = append(g.Regions, r)
+		}
+	}
+	sort.Strings(g.Regions)
+
+	return g
+}
@@ -0,0 +1,622 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+
+	"github.com/andrewsomething/do-api-slugs/api/internal/catalog"
+	"github.com/digitalocean/godo"
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/language/ast"
+	"github.com/graphql-go/graphql/language/parser"
+)
+
+const (
+	maxGraphQLBody = 1 << 20
+
+	// maxGraphQLDepth and maxGraphQLCost bound the work a query can ask for.
+	// Sizes and regions refer to each other, so without them a few levels of
+	// nesting multiply into millions of objects. The depth allows the
+	// introspection query GraphQL clients send.
+	maxGraphQLDepth = 15
+	maxGraphQLCost  = 5000
+	// graphQLListFactor is the number of items a list field is assumed to
+	// return when estimating the cost of a query.
+	graphQLListFactor = 10
+)
+
+type graphQLRequest struct {
+	Query         string                 `json:"query"`
+	OperationName string                 `json:"operationName"`
+	Variables     map[string]interface{} `json:"variables"`
+}
+
+// databaseEngine is the GraphQL view of a single engine's database options.
+type databaseEngine struct {
+	Name     string           `json:"name"`
+	Versions []string         `json:"versions"`
+	Regions  []string         `json:"regions"`
+	Layouts  []databaseLayout `json:"layouts"`
+}
+
+type databaseLayout struct {
+	NumNodes int      `json:"num_nodes"`
+	Sizes    []string `json:"sizes"`
+}
+
+type catalogContextKey struct{}
+
+// graphQLCatalog is the catalog a query is resolved against, with its sizes
+// and regions indexed by slug once per request for the resolvers that join
+// across resources.
+type graphQLCatalog struct {
+	*catalog.Catalog
+	sizes   map[string]godo.Size
+	regions map[string]godo.Region
+}
+
+func newGraphQLCatalog(cat *catalog.Catalog) *graphQLCatalog {
+	c := &graphQLCatalog{
+		Catalog: cat,
+		sizes:   make(map[string]godo.Size, len(cat.Sizes)),
+		regions: make(map[string]godo.Region, len(cat.Regions)),
+	}
+	for _, s := range cat.Sizes {
+		c.sizes[s.Slug] = s
+	}
+	for _, r := range cat.Regions {
+		c.regions[r.Slug] = r
+	}
+
+	return c
+}
+
+// catalogFrom returns the catalog the query is being resolved against.
+func catalogFrom(p graphql.ResolveParams) *graphQLCatalog {
+	cat, _ := p.Context.Value(catalogContextKey{}).(*graphQLCatalog)
+	return cat
+}
+
+// newGraphQLSchema builds the schema served at /graphql. Fields use the same
+// snake_case names as the JSON responses and are resolved from the cached
+// catalog by the default resolver unless they join across resources.
+func newGraphQLSchema() (graphql.Schema, error) {
+	regionType := graphql.NewObject(graphql.ObjectConfig{
+		Name:        "Region",
+		Description: "A DigitalOcean region.",
+		Fields: graphql.Fields{
+			"slug":      &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+			"name":      &graphql.Field{Type: graphql.String},
+			"available": &graphql.Field{Type: graphql.Boolean},
+			"features":  &graphql.Field{Type: graphql.NewList(graphql.String)},
+		},
+	})
+
+	sizeType := graphql.NewObject(graphql.ObjectConfig{
+		Name:        "Size",
+		Description: "A Droplet size. Memory is in MB, disk in GB and transfer in TB.",
+		Fields: graphql.Fields{
+			"slug":          &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+			"description":   &graphql.Field{Type: graphql.String},
+			"vcpus":         &graphql.Field{Type: graphql.Int},
+			"memory":        &graphql.Field{Type: graphql.Int},
+			"disk":          &graphql.Field{Type: graphql.Int},
+			"transfer":      &graphql.Field{Type: graphql.Float},
+			"price_monthly": &graphql.Field{Type: graphql.Float},
+			"price_hourly":  &graphql.Field{Type: graphql.Float},
+			"available":     &graphql.Field{Type: graphql.Boolean},
+			"regions": &graphql.Field{
+				Type: graphql.NewList(regionType),
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					s, _ := p.Source.(godo.Size)
+					return catalogFrom(p).regionsBySlugs(s.Regions), nil
+				},
+			},
+		},
+	})
+
+	// Regions list their sizes, which refer back to regions, so the field is
+	// added once both types exist.
+	regionType.AddFieldConfig("sizes", &graphql.Field{
+		Type: graphql.NewList(sizeType),
+		Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			r, _ := p.Source.(godo.Region)
+			return catalogFrom(p).sizesBySlugs(r.Sizes), nil
+		},
+	})
+
+	imageType := graphql.NewObject(graphql.ObjectConfig{
+		Name:        "Image",
+		Description: "A public Droplet image.",
+		Fields: graphql.Fields{
+			"id":             &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+			"slug":           &graphql.Field{Type: graphql.String},
+			"name":           &graphql.Field{Type: graphql.String},
+			"type":           &graphql.Field{Type: graphql.String},
+			"distribution":   &graphql.Field{Type: graphql.String},
+			"description":    &graphql.Field{Type: graphql.String},
+			"status":         &graphql.Field{Type: graphql.String},
+			"min_disk_size":  &graphql.Field{Type: graphql.Int},
+			"size_gigabytes": &graphql.Field{Type: graphql.Float},
+			"created_at":     &graphql.Field{Type: graphql.String},
+			"regions": &graphql.Field{
+				Type: graphql.NewList(regionType),
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					i, _ := p.Source.(godo.Image)
+					return catalogFrom(p).regionsBySlugs(i.Regions), nil
+				},
+			},
+		},
+	})
+
+	k8sVersionType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "KubernetesVersion",
+		Fields: graphql.Fields{
+			"slug":               &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+			"kubernetes_version": &graphql.Field{Type: graphql.String},
+			"supported_features": &graphql.Field{Type: graphql.NewList(graphql.String)},
+		},
+	})
+
+	k8sOptionsType := graphql.NewObject(graphql.ObjectConfig{
+		Name:        "KubernetesOptions",
+		Description: "The versions, regions and node sizes available to Kubernetes clusters.",
+		Fields: graphql.Fields{
+			"versions": &graphql.Field{Type: graphql.NewList(k8sVersionType)},
+			"regions": &graphql.Field{
+				Type: graphql.NewList(regionType),
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					o, _ := p.Source.(*godo.KubernetesOptions)
+					var slugs []string
+					for _, r := range o.Regions {
+						slugs = append(slugs, r.Slug)
+					}
+					return catalogFrom(p).regionsBySlugs(slugs), nil
+				},
+			},
+			"sizes": &graphql.Field{
+				Type: graphql.NewList(sizeType),
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					o, _ := p.Source.(*godo.KubernetesOptions)
+					var slugs []string
+					for _, s := range o.Sizes {
+						slugs = append(slugs, s.Slug)
+					}
+					return catalogFrom(p).sizesBySlugs(slugs), nil
+				},
+			},
+		},
+	})
+
+	appInstanceSizeType := graphql.NewObject(graphql.ObjectConfig{
+		Name:        "AppInstanceSize",
+		Description: "An App Platform instance size. Prices are in USD.",
+		Fields: graphql.Fields{
+			"slug":                 &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+			"name":                 &graphql.Field{Type: graphql.String},
+			"cpu_type":             &graphql.Field{Type: graphql.String},
+			"cpus":                 &graphql.Field{Type: graphql.String},
+			"memory_bytes":         &graphql.Field{Type: graphql.String},
+			"usd_per_month":        &graphql.Field{Type: graphql.String},
+			"usd_per_second":       &graphql.Field{Type: graphql.String},
+			"tier_slug":            &graphql.Field{Type: graphql.String},
+			"scalable":             &graphql.Field{Type: graphql.Boolean},
+			"single_instance_only": &graphql.Field{Type: graphql.Boolean},
+			"deprecation_intent":   &graphql.Field{Type: graphql.Boolean},
+		},
+	})
+
+	databaseLayoutType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "DatabaseLayout",
+		Fields: graphql.Fields{
+			"num_nodes": &graphql.Field{Type: graphql.Int},
+			"sizes":     &graphql.Field{Type: graphql.NewList(graphql.String)},
+		},
+	})
+
+	databaseEngineType := graphql.NewObject(graphql.ObjectConfig{
+		Name:        "DatabaseEngine",
+		Description: "A managed database engine and the options it supports.",
+		Fields: graphql.Fields{
+			"name":     &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+			"versions": &graphql.Field{Type: graphql.NewList(graphql.String)},
+			"layouts":  &graphql.Field{Type: graphql.NewList(databaseLayoutType)},
+			"regions": &graphql.Field{
+				Type: graphql.NewList(regionType),
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					e, _ := p.Source.(databaseEngine)
+					return catalogFrom(p).regionsBySlugs(e.Regions), nil
+				},
+			},
+		},
+	})
+
+	slugArgs := graphql.FieldConfigArgument{
+		"slug": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.String)},
+	}
+	queryType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Query",
+		Fields: graphql.Fields{
+			"retrieved_at": &graphql.Field{
+				Type: graphql.String,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return catalogFrom(p).RetrievedAt.Format("Mon Jan _2 15:04:05 2006 UTC"), nil
+				},
+			},
+			"sizes": &graphql.Field{
+				Type: graphql.NewList(sizeType),
+				Args: graphql.FieldConfigArgument{
+					"region":    &graphql.ArgumentConfig{Type: graphql.String},
+					"available": &graphql.ArgumentConfig{Type: graphql.Boolean},
+				},
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					region, _ := p.Args["region"].(string)
+					available, filterAvailable := p.Args["available"].(bool)
+					var sizes []godo.Size
+					for _, s := range catalogFrom(p).Sizes {
+						if region != "" && !contains(s.Regions, region) {
+							continue
+						}
+						if filterAvailable && s.Available != available {
+							continue
+						}
+						sizes = append(sizes, s)
+					}
+					return sizes, nil
+				},
+			},
+			"size": &graphql.Field{
+				Type: sizeType,
+				Args: slugArgs,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					slug, _ := p.Args["slug"].(string)
+					if sizes := catalogFrom(p).sizesBySlugs([]string{slug}); len(sizes) > 0 {
+						return sizes[0], nil
+					}
+					return nil, nil
+				},
+			},
+			"regions": &graphql.Field{
+				Type: graphql.NewList(regionType),
+				Args: graphql.FieldConfigArgument{
+					"available": &graphql.ArgumentConfig{Type: graphql.Boolean},
+				},
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					available, filterAvailable := p.Args["available"].(bool)
+					var regions []godo.Region
+					for _, r := range catalogFrom(p).Regions {
+						if !filterAvailable || r.Available == available {
+							regions = append(regions, r)
+						}
+					}
+					return regions, nil
+				},
+			},
+			"region": &graphql.Field{
+				Type: regionType,
+				Args: slugArgs,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					slug, _ := p.Args["slug"].(string)
+					if regions := catalogFrom(p).regionsBySlugs([]string{slug}); len(regions) > 0 {
+						return regions[0], nil
+					}
+					return nil, nil
+				},
+			},
+			"images": &graphql.Field{
+				Type:        graphql.NewList(imageType),
+				Description: "Public images. Type is apps or distros; both are listed if omitted.",
+				Args: graphql.FieldConfigArgument{
+					"type":   &graphql.ArgumentConfig{Type: graphql.String},
+					"region": &graphql.ArgumentConfig{Type: graphql.String},
+				},
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					imageType, _ := p.Args["type"].(string)
+					region, _ := p.Args["region"].(string)
+					cat := catalogFrom(p)
+					var lists [][]godo.Image
+					switch imageType {
+					case "":
+						lists = [][]godo.Image{cat.AppImages, cat.DistroImages}
+					case "apps":
+						lists = [][]godo.Image{cat.AppImages}
+					case "distros":
+						lists = [][]godo.Image{cat.DistroImages}
+					}
+					var images []godo.Image
+					for _, list := range lists {
+						for _, i := range list {
+							if region == "" || contains(i.Regions, region) {
+								images = append(images, i)
+							}
+						}
+					}
+					return images, nil
+				},
+			},
+			"image": &graphql.Field{
+				Type: imageType,
+				Args: slugArgs,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					slug, _ := p.Args["slug"].(string)
+					cat := catalogFrom(p)
+					for _, list := range [][]godo.Image{cat.AppImages, cat.DistroImages} {
+						for _, i := range list {
+							if i.Slug == slug {
+								return i, nil
+							}
+						}
+					}
+					return nil, nil
+				},
+			},
+			"kubernetes_options": &graphql.Field{
+				Type: k8sOptionsType,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					if o := catalogFrom(p).K8sOptions; o != nil {
+						return o, nil
+					}
+					return nil, nil
+				},
+			},
+			"app_instance_sizes": &graphql.Field{
+				Type: graphql.NewList(appInstanceSizeType),
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return catalogFrom(p).AppInstanceSizes, nil
+				},
+			},
+			"database_engines": &graphql.Field{
+				Type: graphql.NewList(databaseEngineType),
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return databaseEngines(catalogFrom(p).Catalog), nil
+				},
+			},
+			"database_engine": &graphql.Field{
+				Type: databaseEngineType,
+				Args: graphql.FieldConfigArgument{
+					"name": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.String)},
+				},
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					name, _ := p.Args["name"].(string)
+					for _, e := range databaseEngines(catalogFrom(p).Catalog) {
+						if e.Name == name {
+							return e, nil
+						}
+					}
+					return nil, nil
+				},
+			},
+		},
+	})
+
+	return graphql.NewSchema(graphql.SchemaConfig{Query: queryType})
+}
+
+// graphQL executes queries sent either as a POST body or in the query
+// parameters of a GET request.
+func (h *handler) graphQL(w http.ResponseWriter, r *http.Request) {
+	var req graphQLRequest
+	switch r.Method {
+	case http.MethodGet:
+		q := r.URL.Query()
+		req.Query = q.Get("query")
+		req.OperationName = q.Get("operationName")
+		if v := q.Get("variables"); v != "" {
+			if err := json.Unmarshal([]byte(v), &req.Variables); err != nil {
+				writeJSONError(w, http.StatusBadRequest)
+				return
+			}
+		}
+	case http.MethodPost:
+		if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxGraphQLBody)).Decode(&req); err != nil {
+			writeJSONError(w, http.StatusBadRequest)
+			return
+		}
+	default:
+		w.Header().Set("Allow", http.MethodGet+", "+http.MethodPost)
+		writeJSONError(w, http.StatusMethodNotAllowed)
+		return
+	}
+	if req.Query == "" {
+		writeJSONError(w, http.StatusBadRequest)
+		return
+	}
+
+	// Queries that don't parse are left to graphql.Do, which reports the
+	// syntax error in the usual GraphQL form.
+	if doc, err := parser.Parse(parser.ParseParams{Source: req.Query}); err == nil {
+		if err := checkGraphQLCost(h.graphQLSchema, doc); err != nil {
+			writeErrorResponse(w, http.StatusBadRequest, errorResponse{Message: err.Error()})
+			return
+		}
+	}
+
+	cat, err := h.catalogFor(r.Context(), catalog.ResourceNames()...)
+	if err != nil {
+		h.writeUpstreamError(w, r, err)
+		return
+	}
+
+	result := graphql.Do(graphql.Params{
+		Schema:         h.graphQLSchema,
+		RequestString:  req.Query,
+		OperationName:  req.OperationName,
+		VariableValues: req.Variables,
+		Context:        context.WithValue(r.Context(), catalogContextKey{}, newGraphQLCatalog(cat)),
+	})
+
+	writeJSONResponse(w, result)
+}
+
+// graphQLCost estimates the work a query asks for: each field counts once,
+// multiplied by graphQLListFactor for every list it is nested in.
+// Introspection only walks the schema, which is small and fixed, so its lists
+// aren't weighted.
+type graphQLCost struct {
+	schema    graphql.Schema
+	fragments map[string]*ast.FragmentDefinition
+	// expanding holds the fragments being expanded, so that cyclic spreads,
+	// which validation rejects later, don't recurse forever.
+	expanding map[string]bool
+}
+
+// checkGraphQLCost returns an error if any operation in doc is nested deeper
+// than maxGraphQLDepth or costs more than maxGraphQLCost.
+func checkGraphQLCost(schema graphql.Schema, doc *ast.Document) error {
+	c := &graphQLCost{
+		schema:    schema,
+		fragments: make(map[string]*ast.FragmentDefinition),
+		expanding: make(map[string]bool),
+	}
+	for _, def := range doc.Definitions {
+		if f, ok := def.(*ast.FragmentDefinition); ok {
+			c.fragments[f.Name.Value] = f
+		}
+	}
+	for _, def := range doc.Definitions {
+		if op, ok := def.(*ast.OperationDefinition); ok {
+			if _, err := c.selectionSet(schema.QueryType(), op.SelectionSet, 1, false); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// selectionSet returns the cost of the selections of a value of type t at
+// the given depth. It stops as soon as the cost exceeds maxGraphQLCost, so
+// fragments spread many times over can't make the check itself expensive.
+func (c *graphQLCost) selectionSet(t graphql.Type, set *ast.SelectionSet, depth int, introspection bool) (int, error) {
+	if set == nil {
+		return 0, nil
+	}
+
+	cost := 0
+	for _, sel := range set.Selections {
+		var n int
+		var err error
+		switch sel := sel.(type) {
+		case *ast.Field:
+			n, err = c.field(t, sel, depth, introspection)
+		case *ast.InlineFragment:
+			typ := t
+			if sel.TypeCondition != nil {
+				typ = c.schema.Type(sel.TypeCondition.Name.Value)
+			}
+			n, err = c.selectionSet(typ, sel.SelectionSet, depth, introspection)
+		case *ast.FragmentSpread:
+			name := sel.Name.Value
+			f, ok := c.fragments[name]
+			if !ok || c.expanding[name] {
+				continue
+			}
+			c.expanding[name] = true
+			n, err = c.selectionSet(c.schema.Type(f.TypeCondition.Name.Value), f.SelectionSet, depth, introspection)
+			delete(c.expanding, name)
+		}
+		if err != nil {
+			return 0, err
+		}
+		cost += n
+		if cost > maxGraphQLCost {
+			return 0, fmt.Errorf("query is too expensive: its estimated cost exceeds %d", maxGraphQLCost)
+		}
+	}
+
+	return cost, nil
+}
+
+// field returns the cost of a field selected on a value of type t.
+func (c *graphQLCost) field(t graphql.Type, f *ast.Field, depth int, introspection bool) (int, error) {
+	if depth > maxGraphQLDepth {
+		return 0, fmt.Errorf("query is too deep: fields are nested more than %d levels", maxGraphQLDepth)
+	}
+
+	var def *graphql.FieldDefinition
+	switch f.Name.Value {
+	case "__typename":
+		return 1, nil
+	case "__schema":
+		def, introspection = graphql.SchemaMetaFieldDef, true
+	case "__type":
+		def, introspection = graphql.TypeMetaFieldDef, true
+	default:
+		if obj, ok := graphql.GetNamed(t).(*graphql.Object); ok {
+			def = obj.Fields()[f.Name.Value]
+		}
+	}
+	// Unknown fields are left for validation to reject.
+	if def == nil {
+		return 1, nil
+	}
+
+	children, err := c.selectionSet(def.Type, f.SelectionSet, depth+1, introspection)
+	if err != nil {
+		return 0, err
+	}
+	cost := 1 + children
+	if _, ok := graphql.GetNullable(def.Type).(*graphql.List); ok && !introspection {
+		cost *= graphQLListFactor
+	}
+
+	return cost, nil
+}
+
+// regionsBySlugs returns the catalog's regions with the given slugs, in the
+// order given. Unknown slugs are skipped.
+func (c *graphQLCatalog) regionsBySlugs(slugs []string) []godo.Region {
+	regions := make([]godo.Region, 0, len(slugs))
+	for _, slug := range slugs {
+		if r, ok := c.regions[slug]; ok {
+			regions = append(regions, r)
+		}
+	}
+
+	return regions
+}
+
+// sizesBySlugs returns the catalog's sizes with the given slugs, in the order
+// given. Unknown slugs are skipped.
+func (c *graphQLCatalog) sizesBySlugs(slugs []string) []godo.Size {
+	sizes := make([]godo.Size, 0, len(slugs))
+	for _, slug := range slugs {
+		if s, ok := c.sizes[slug]; ok {
+			sizes = append(sizes, s)
+		}
+	}
+
+	return sizes
+}
+
+// databaseEngines converts the generic database options to typed engines,
+// sorted by name.
+func databaseEngines(cat *catalog.Catalog) []databaseEngine {
+	engines := make([]databaseEngine, 0, len(cat.DatabaseOptions))
+	for name, v := range cat.DatabaseOptions {
+		options, _ := v.(map[string]interface{})
+		e := databaseEngine{
+			Name:     name,
+			Versions: stringSlice(options["versions"]),
+			Regions:  stringSlice(options["regions"]),
+		}
+		layouts, _ := options["layouts"].([]interface{})
+		for _, l := range layouts {
+			layout, _ := l.(map[string]interface{})
+			nodes, _ := layout["num_nodes"].(float64)
+			e.Layouts = append(e.Layouts, databaseLayout{
+				NumNodes: int(nodes),
+				Sizes:    stringSlice(layout["sizes"]),
+			})
+		}
+		engines = append(engines, e)
+	}
+	sort.Slice(engines, func(i, j int) bool {
+		return engines[i].Name < engines[j].Name
+	})
+
+	return engines
+}
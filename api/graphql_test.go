@@ -0,0 +1,162 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/andrewsomething/do-api-slugs/api/internal/catalog"
+	"github.com/digitalocean/godo"
+	"github.com/graphql-go/graphql/language/parser"
+)
+
+// introspectionQuery is the query GraphiQL and most GraphQL clients send to
+// discover the schema.
+const introspectionQuery = `
+query IntrospectionQuery {
+  __schema {
+    queryType { name }
+    mutationType { name }
+    subscriptionType { name }
+    types { ...FullType }
+    directives { name description locations args { ...InputValue } }
+  }
+}
+fragment FullType on __Type {
+  kind name description
+  fields(includeDeprecated: true) {
+    name description
+    args { ...InputValue }
+    type { ...TypeRef }
+    isDeprecated deprecationReason
+  }
+  inputFields { ...InputValue }
+  interfaces { ...TypeRef }
+  enumValues(includeDeprecated: true) { name description isDeprecated deprecationReason }
+  possibleTypes { ...TypeRef }
+}
+fragment InputValue on __InputValue {
+  name description
+  type { ...TypeRef }
+  defaultValue
+}
+fragment TypeRef on __Type {
+  kind name
+  ofType { kind name ofType { kind name ofType { kind name ofType { kind name ofType { kind name
+    ofType { kind name ofType { kind name ofType { kind name ofType { kind name } } } } } } } } }
+}`
+
+func TestCheckGraphQLCost(t *testing.T) {
+	schema, err := newGraphQLSchema()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		query   string
+		wantErr string
+	}{
+		{
+			name:  "flat",
+			query: `{ retrieved_at sizes { slug price_monthly } }`,
+		},
+		{
+			name:  "one join",
+			query: `{ sizes { slug regions { slug name } } }`,
+		},
+		{
+			name:  "introspection",
+			query: introspectionQuery,
+		},
+		{
+			name:    "nested joins",
+			query:   `{ sizes { regions { sizes { regions { sizes { regions { sizes { slug } } } } } } } }`,
+			wantErr: "too expensive",
+		},
+		{
+			name:    "nested joins in fragments",
+			query:   `{ sizes { ...R } } fragment R on Size { regions { sizes { regions { slug } } } }`,
+			wantErr: "too expensive",
+		},
+		{
+			name:    "deep introspection",
+			query:   `{ __type(name: "Size") { fields { type { ofType { ofType { ofType { ofType { ofType { ofType { ofType { ofType { ofType { ofType { ofType { ofType { name } } } } } } } } } } } } } } } }`,
+			wantErr: "too deep",
+		},
+		{
+			name:  "cyclic fragments",
+			query: `{ ...A } fragment A on Query { ...B } fragment B on Query { ...A }`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc, err := parser.Parse(parser.ParseParams{Source: tt.query})
+			if err != nil {
+				t.Fatal(err)
+			}
+			err = checkGraphQLCost(schema, doc)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("got error %v, want none", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("got error %v, want one containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestGraphQLHandler(t *testing.T) {
+	schema, err := newGraphQLSchema()
+	if err != nil {
+		t.Fatal(err)
+	}
+	h := &handler{refresher: newRefresher(nil, time.Minute), graphQLSchema: schema}
+	h.refresher.seed(&catalog.Catalog{
+		RetrievedAt: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		Sizes:       []godo.Size{{Slug: "s-1", Regions: []string{"ams3", "nyc1"}}},
+		Regions: []godo.Region{
+			{Slug: "nyc1", Sizes: []string{"s-1"}},
+			{Slug: "ams3", Sizes: []string{"s-1"}},
+		},
+	})
+
+	tests := []struct {
+		name   string
+		query  string
+		status int
+		want   string
+	}{
+		{
+			name:   "join",
+			query:  `{ size(slug: "s-1") { regions { slug } } }`,
+			status: http.StatusOK,
+			want:   `{"data":{"size":{"regions":[{"slug":"ams3"},{"slug":"nyc1"}]}}}`,
+		},
+		{
+			name:   "too expensive",
+			query:  `{ sizes { regions { sizes { regions { sizes { regions { sizes { regions { slug } } } } } } } } }`,
+			status: http.StatusBadRequest,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body, _ := json.Marshal(graphQLRequest{Query: tt.query})
+			rec := httptest.NewRecorder()
+			h.graphQL(rec, httptest.NewRequest(http.MethodPost, "/graphql", strings.NewReader(string(body))))
+
+			if rec.Code != tt.status {
+				t.Fatalf("got status %d, want %d: %s", rec.Code, tt.status, rec.Body)
+			}
+			if tt.want != "" && strings.TrimSpace(rec.Body.String()) != tt.want {
+				t.Errorf("got %s, want %s", rec.Body, tt.want)
+			}
+		})
+	}
+}
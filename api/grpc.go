@@ -0,0 +1,321 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/andrewsomething/do-api-slugs/api/internal/catalog"
+	slugsv1 "github.com/andrewsomething/do-api-slugs/api/proto/slugs/v1"
+	"github.com/digitalocean/godo"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	defaultGRPCPort = "50051"
+)
+
+// grpcServer implements SlugsService using the same catalog as the HTTP
+// handlers.
+type grpcServer struct {
+	slugsv1.UnimplementedSlugsServiceServer
+	h *handler
+}
+
+// catalog returns the catalog once the named resources have been retrieved.
+func (s *grpcServer) catalog(ctx context.Context, resources ...string) (*catalog.Catalog, error) {
+	cat, err := s.h.catalogFor(ctx, resources...)
+	if err != nil {
+		return nil, s.upstreamStatus(ctx, err)
+	}
+
+	return cat, nil
+}
+
+// upstreamStatus logs err and maps it to a gRPC status in the same way
+// writeUpstreamError maps it to an HTTP status:
+//
+//   - Unavailable if the API rejected our token, or failed in some other way
+//   - ResourceExhausted with RetryInfo if we are being rate limited
+//   - DeadlineExceeded if the call timed out
+//   - Canceled if the client went away
+//
+// Anything else is reported as Internal.
+func (s *grpcServer) upstreamStatus(ctx context.Context, err error) error {
+	failure, upstreamID := classifyUpstreamError(err)
+	if failure == upstreamFailureCanceled {
+		return status.Error(codes.Canceled, "request canceled")
+	}
+	loggerFrom(ctx).Error("upstream request failed",
+		"error", err,
+		"upstream_request_id", upstreamID,
+	)
+
+	msg := upstreamFailureMessages[failure]
+	switch failure {
+	case upstreamFailureTimeout:
+		return status.Error(codes.DeadlineExceeded, msg)
+	case upstreamFailureRateLimited:
+		st, detailErr := status.New(codes.ResourceExhausted, msg).WithDetails(&errdetails.RetryInfo{
+			RetryDelay: durationpb.New(s.h.retryAfter()),
+		})
+		if detailErr != nil {
+			return status.Error(codes.ResourceExhausted, msg)
+		}
+		return st.Err()
+	case upstreamFailureAuth, upstreamFailureAPI:
+		return status.Error(codes.Unavailable, msg)
+	default:
+		return status.Error(codes.Internal, "internal error")
+	}
+}
+
+func (s *grpcServer) ListSizes(ctx context.Context, req *slugsv1.ListSizesRequest) (*slugsv1.ListSizesResponse, error) {
+	cat, err := s.catalog(ctx, catalog.ResourceSizes)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &slugsv1.ListSizesResponse{RetrievedAt: timestamppb.New(oldestRetrievedAt(cat, catalog.ResourceSizes))}
+	for _, size := range cat.Sizes {
+		resp.Sizes = append(resp.Sizes, &slugsv1.Size{
+			Slug:         size.Slug,
+			Description:  size.Description,
+			Memory:       int32(size.Memory),
+			Vcpus:        int32(size.Vcpus),
+			Disk:         int32(size.Disk),
+			Transfer:     size.Transfer,
+			PriceMonthly: size.PriceMonthly,
+			PriceHourly:  size.PriceHourly,
+			Regions:      size.Regions,
+			Available:    size.Available,
+		})
+	}
+
+	return resp, nil
+}
+
+func (s *grpcServer) ListRegions(ctx context.Context, req *slugsv1.ListRegionsRequest) (*slugsv1.ListRegionsResponse, error) {
+	cat, err := s.catalog(ctx, catalog.ResourceRegions)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &slugsv1.ListRegionsResponse{RetrievedAt: timestamppb.New(oldestRetrievedAt(cat, catalog.ResourceRegions))}
+	for _, region := range cat.Regions {
+		resp.Regions = append(resp.Regions, &slugsv1.Region{
+			Slug:      region.Slug,
+			Name:      region.Name,
+			Sizes:     region.Sizes,
+			Available: region.Available,
+			Features:  region.Features,
+		})
+	}
+
+	return resp, nil
+}
+
+func (s *grpcServer) ListImages(ctx context.Context, req *slugsv1.ListImagesRequest) (*slugsv1.ListImagesResponse, error) {
+	var resources []string
+	switch req.GetType() {
+	case slugsv1.ListImagesRequest_IMAGE_TYPE_APPS:
+		resources = []string{catalog.ResourceAppImages}
+	case slugsv1.ListImagesRequest_IMAGE_TYPE_DISTROS:
+		resources = []string{catalog.ResourceDistroImages}
+	default:
+		resources = []string{catalog.ResourceAppImages, catalog.ResourceDistroImages}
+	}
+	cat, err := s.catalog(ctx, resources...)
+	if err != nil {
+		return nil, err
+	}
+
+	var lists [][]godo.Image
+	for _, resource := range resources {
+		if resource == catalog.ResourceAppImages {
+			lists = append(lists, cat.AppImages)
+		} else {
+			lists = append(lists, cat.DistroImages)
+		}
+	}
+
+	resp := &slugsv1.ListImagesResponse{RetrievedAt: timestamppb.New(oldestRetrievedAt(cat, resources...))}
+	for _, images := range lists {
+		for _, image := range images {
+			resp.Images = append(resp.Images, &slugsv1.Image{
+				Id:            int64(image.ID),
+				Slug:          image.Slug,
+				Name:          image.Name,
+				Type:          image.Type,
+				Distribution:  image.Distribution,
+				Public:        image.Public,
+				Regions:       image.Regions,
+				MinDiskSize:   int32(image.MinDiskSize),
+				SizeGigabytes: image.SizeGigaBytes,
+				CreatedAt:     image.Created,
+				Description:   image.Description,
+				Status:        image.Status,
+			})
+		}
+	}
+
+	return resp, nil
+}
+
+func (s *grpcServer) GetKubernetesOptions(ctx context.Context, req *slugsv1.GetKubernetesOptionsRequest) (*slugsv1.GetKubernetesOptionsResponse, error) {
+	cat, err := s.catalog(ctx, catalog.ResourceK8sOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	options := &slugsv1.KubernetesOptions{}
+	if cat.K8sOptions != nil {
+		for _, v := range cat.K8sOptions.Versions {
+			options.Versions = append(options.Versions, &slugsv1.KubernetesVersion{
+				Slug:              v.Slug,
+				KubernetesVersion: v.KubernetesVersion,
+				SupportedFeatures: v.SupportedFeatures,
+			})
+		}
+		for _, r := range cat.K8sOptions.Regions {
+			options.Regions = append(options.Regions, &slugsv1.KubernetesRegion{Slug: r.Slug, Name: r.Name})
+		}
+		for _, s := range cat.K8sOptions.Sizes {
+			options.Sizes = append(options.Sizes, &slugsv1.KubernetesNodeSize{Slug: s.Slug, Name: s.Name})
+		}
+	}
+
+	return &slugsv1.GetKubernetesOptionsResponse{
+		Options:     options,
+		RetrievedAt: timestamppb.New(oldestRetrievedAt(cat, catalog.ResourceK8sOptions)),
+	}, nil
+}
+
+func (s *grpcServer) ListAppInstanceSizes(ctx context.Context, req *slugsv1.ListAppInstanceSizesRequest) (*slugsv1.ListAppInstanceSizesResponse, error) {
+	cat, err := s.catalog(ctx, catalog.ResourceAppInstanceSizes)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &slugsv1.ListAppInstanceSizesResponse{RetrievedAt: timestamppb.New(oldestRetrievedAt(cat, catalog.ResourceAppInstanceSizes))}
+	for _, size := range cat.AppInstanceSizes {
+		resp.Sizes = append(resp.Sizes, &slugsv1.AppInstanceSize{
+			Slug:                  size.Slug,
+			Name:                  size.Name,
+			CpuType:               string(size.CPUType),
+			Cpus:                  size.CPUs,
+			MemoryBytes:           size.MemoryBytes,
+			UsdPerMonth:           size.USDPerMonth,
+			UsdPerSecond:          size.USDPerSecond,
+			TierSlug:              size.TierSlug,
+			Scalable:              size.Scalable,
+			SingleInstanceOnly:    size.SingleInstanceOnly,
+			DeprecationIntent:     size.DeprecationIntent,
+			BandwidthAllowanceGib: size.BandwidthAllowanceGib,
+		})
+	}
+
+	return resp, nil
+}
+
+func (s *grpcServer) GetDatabaseOptions(ctx context.Context, req *slugsv1.GetDatabaseOptionsRequest) (*slugsv1.GetDatabaseOptionsResponse, error) {
+	cat, err := s.catalog(ctx, catalog.ResourceDatabaseOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	options, err := structpb.NewStruct(cat.DatabaseOptions)
+	if err != nil {
+		loggerFrom(ctx).Error("invalid database options", "error", err)
+		return nil, status.Error(codes.Internal, "invalid database options")
+	}
+
+	return &slugsv1.GetDatabaseOptionsResponse{
+		Options:     options,
+		RetrievedAt: timestamppb.New(oldestRetrievedAt(cat, catalog.ResourceDatabaseOptions)),
+	}, nil
+}
+
+// WatchChanges streams the changes found by each background refresh until
+// the client goes away or the server shuts down. Refreshes without changes are not sent.
+func (s *grpcServer) WatchChanges(req *slugsv1.WatchChangesRequest, stream slugsv1.SlugsService_WatchChangesServer) error {
+	events, unsubscribe := s.h.refresher.subscribe()
+	defer unsubscribe()
+
+	for {
+		select {
+		case <-stream.Context().Done():
+			return nil
+		case <-s.h.stopping:
+			return nil
+		case event := <-events:
+			if len(event.Changes) == 0 {
+				continue
+			}
+			msg, err := changeEventToProto(event)
+			if err != nil {
+				loggerFrom(stream.Context()).Error("unable to send change event", "error", err)
+				continue
+			}
+			if err := stream.Send(msg); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// stopGRPC stops srv once in-flight RPCs finish, or immediately if ctx is
+// done first.
+func stopGRPC(ctx context.Context, srv *grpc.Server) {
+	stopped := make(chan struct{})
+	go func() {
+		srv.GracefulStop()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+	case <-ctx.Done():
+		slog.Error("gRPC server did not shut down cleanly", "error", ctx.Err())
+		srv.Stop()
+	}
+}
+
+func changeEventToProto(event refreshEvent) (*slugsv1.CatalogChangeEvent, error) {
+	id, err := newID()
+	if err != nil {
+		return nil, err
+	}
+
+	msg := &slugsv1.CatalogChangeEvent{
+		Id:          id,
+		RetrievedAt: timestamppb.New(event.Current.RetrievedAt),
+	}
+	for _, c := range event.Changes {
+		pc := &slugsv1.Change{
+			Resource: c.Resource,
+			Slug:     c.Slug,
+			Action:   c.Action,
+		}
+		if len(c.Fields) > 0 {
+			pc.Fields = make(map[string]*slugsv1.FieldChange, len(c.Fields))
+		}
+		for name, f := range c.Fields {
+			fc := &slugsv1.FieldChange{Old: &structpb.Value{}, New: &structpb.Value{}}
+			if err := fc.Old.UnmarshalJSON(f.Old); err != nil {
+				return nil, err
+			}
+			if err := fc.New.UnmarshalJSON(f.New); err != nil {
+				return nil, err
+			}
+			pc.Fields[name] = fc
+		}
+		msg.Changes = append(msg.Changes, pc)
+	}
+
+	return msg, nil
+}
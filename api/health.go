@@ -0,0 +1,81 @@
+package main
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/digitalocean/godo"
+)
+
+// resourceHealth describes the freshness of one cached resource. Source is
+// "api" once a refresh has succeeded, or "snapshot" if it was loaded from
+// SNAPSHOT_PATH at startup.
+type resourceHealth struct {
+	Resource         string     `json:"resource"`
+	Ready            bool       `json:"ready"`
+	Source           string     `json:"source,omitempty"`
+	RetrievedAt      *time.Time `json:"retrieved_at,omitempty"`
+	AgeSeconds       *float64   `json:"age_seconds,omitempty"`
+	LastRefreshError string     `json:"last_refresh_error,omitempty"`
+}
+
+type healthResponse struct {
+	Status    string           `json:"status"`
+	Token     string           `json:"token,omitempty"`
+	Resources []resourceHealth `json:"resources,omitempty"`
+}
+
+// healthz reports that the process is up. It never calls upstream, and is
+// what the App Platform health check uses.
+func (h *handler) healthz(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Cache-Control", "no-store")
+	writeJSONResponse(w, healthResponse{Status: "ok"})
+}
+
+// readyz reports whether the API can serve the catalog: the token hasn't
+// been rejected and every resource has been fetched at least once, or loaded
+// from a snapshot. It responds with a 503 until then. Resources are refreshed
+// separately, so each is listed with its own freshness and last error.
+func (h *handler) readyz(w http.ResponseWriter, r *http.Request) {
+	resp := healthResponse{Status: "ready", Token: "ok"}
+	for _, status := range h.refresher.status() {
+		rh := resourceHealth{Resource: status.Name}
+		if status.LastErr != nil {
+			rh.LastRefreshError = status.LastErr.Error()
+			if tokenRejected(status.LastErr) {
+				resp.Token = "rejected"
+				resp.Status = "unavailable"
+			}
+		}
+		if !status.RetrievedAt.IsZero() {
+			retrievedAt := status.RetrievedAt
+			age := time.Since(retrievedAt).Seconds()
+			rh.Ready = true
+			rh.Source = "api"
+			if status.FromSnapshot {
+				rh.Source = "snapshot"
+			}
+			rh.RetrievedAt = &retrievedAt
+			rh.AgeSeconds = &age
+		} else {
+			resp.Status = "unavailable"
+		}
+		resp.Resources = append(resp.Resources, rh)
+	}
+
+	w.Header().Set("Cache-Control", "no-store")
+	if resp.Status != "ready" {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+	writeJSONResponse(w, resp)
+}
+
+// tokenRejected reports whether err is the DigitalOcean API refusing our
+// token.
+func tokenRejected(err error) bool {
+	var errResp *godo.ErrorResponse
+	return errors.As(err, &errResp) && errResp.Response != nil &&
+		(errResp.Response.StatusCode == http.StatusUnauthorized || errResp.Response.StatusCode == http.StatusForbidden)
+}
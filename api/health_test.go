@@ -0,0 +1,135 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/andrewsomething/do-api-slugs/api/internal/catalog"
+	"github.com/digitalocean/godo"
+)
+
+func TestReadyz(t *testing.T) {
+	retrieved := time.Now().Add(-time.Minute)
+	// A catalog from a snapshot saved before resources were retrieved
+	// separately has every resource.
+	snapshot := &catalog.Catalog{RetrievedAt: retrieved}
+	sizesOnly := &catalog.Catalog{Retrieved: map[string]time.Time{catalog.ResourceSizes: retrieved}}
+	rejected := &godo.ErrorResponse{
+		Response: &http.Response{
+			StatusCode: http.StatusUnauthorized,
+			Request:    httptest.NewRequest(http.MethodGet, "https://api.digitalocean.com/v2/sizes", nil),
+		},
+		Message: "Unable to authenticate you",
+	}
+
+	tests := []struct {
+		name string
+		cat  *catalog.Catalog
+		// refreshed is seeded as retrieved by a refresh rather than a
+		// snapshot.
+		refreshed bool
+		lastErr   map[string]error
+		status    int
+		token     string
+		// ready lists the resources expected to be ready, with their
+		// source; every other resource must not be.
+		ready    map[string]string
+		errors   map[string]string
+		wantStat string
+	}{
+		{
+			name:     "nothing retrieved",
+			status:   http.StatusServiceUnavailable,
+			token:    "ok",
+			wantStat: "unavailable",
+		},
+		{
+			name:      "one resource retrieved",
+			cat:       sizesOnly,
+			refreshed: true,
+			lastErr:   map[string]error{catalog.ResourceDatabaseOptions: errors.New("boom")},
+			status:    http.StatusServiceUnavailable,
+			token:     "ok",
+			ready:     map[string]string{catalog.ResourceSizes: "api"},
+			errors:    map[string]string{catalog.ResourceDatabaseOptions: "boom"},
+			wantStat:  "unavailable",
+		},
+		{
+			name:     "every resource from a snapshot",
+			cat:      snapshot,
+			lastErr:  map[string]error{catalog.ResourceSizes: errors.New("boom")},
+			status:   http.StatusOK,
+			token:    "ok",
+			ready:    allResources("snapshot"),
+			errors:   map[string]string{catalog.ResourceSizes: "boom"},
+			wantStat: "ready",
+		},
+		{
+			name:     "token rejected",
+			cat:      snapshot,
+			lastErr:  map[string]error{catalog.ResourceRegions: rejected},
+			status:   http.StatusServiceUnavailable,
+			token:    "rejected",
+			ready:    allResources("snapshot"),
+			errors:   map[string]string{catalog.ResourceRegions: rejected.Error()},
+			wantStat: "unavailable",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := &handler{refresher: newRefresher(nil, time.Minute)}
+			if tt.cat != nil {
+				h.refresher.seed(tt.cat)
+			}
+			for name, state := range h.refresher.resources {
+				if tt.refreshed {
+					state.fromSnapshot = false
+				}
+				state.lastErr = tt.lastErr[name]
+			}
+
+			rec := httptest.NewRecorder()
+			h.readyz(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+
+			if rec.Code != tt.status {
+				t.Errorf("got status %d, want %d", rec.Code, tt.status)
+			}
+			var got healthResponse
+			if err := json.NewDecoder(rec.Body).Decode(&got); err != nil {
+				t.Fatal(err)
+			}
+			if got.Status != tt.wantStat || got.Token != tt.token {
+				t.Errorf("got status %q and token %q, want %q and %q", got.Status, got.Token, tt.wantStat, tt.token)
+			}
+			if len(got.Resources) != len(catalog.Resources) {
+				t.Fatalf("got %d resources, want %d", len(got.Resources), len(catalog.Resources))
+			}
+			for _, rh := range got.Resources {
+				source, ready := tt.ready[rh.Resource]
+				if rh.Ready != ready || rh.Source != source {
+					t.Errorf("%s: got ready %v from %q, want %v from %q", rh.Resource, rh.Ready, rh.Source, ready, source)
+				}
+				if (rh.RetrievedAt != nil) != ready || (rh.AgeSeconds != nil) != ready {
+					t.Errorf("%s: got retrieved_at %v and age %v, want them only if ready", rh.Resource, rh.RetrievedAt, rh.AgeSeconds)
+				}
+				if rh.LastRefreshError != tt.errors[rh.Resource] {
+					t.Errorf("%s: got error %q, want %q", rh.Resource, rh.LastRefreshError, tt.errors[rh.Resource])
+				}
+			}
+		})
+	}
+}
+
+// allResources maps every resource to v.
+func allResources(v string) map[string]string {
+	m := make(map[string]string)
+	for _, name := range catalog.ResourceNames() {
+		m[name] = v
+	}
+
+	return m
+}
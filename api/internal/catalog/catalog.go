@@ -0,0 +1,164 @@
+// Package catalog retrieves the DigitalOcean resources served by the API and
+// saves and loads point-in-time snapshots of them.
+package catalog
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/digitalocean/godo"
+)
+
+// Catalog is a point-in-time copy of every resource served by the API. Each
+// resource is retrieved on its own, so Retrieved records when each was last
+// retrieved, and RetrievedAt when the most recent of them was.
+type Catalog struct {
+	Sizes            []godo.Size             `json:"sizes"`
+	Regions          []godo.Region           `json:"regions"`
+	AppImages        []godo.Image            `json:"app_images"`
+	DistroImages     []godo.Image            `json:"distro_images"`
+	K8sOptions       *godo.KubernetesOptions `json:"k8s_options"`
+	AppInstanceSizes []godo.AppInstanceSize  `json:"app_instance_sizes"`
+	DatabaseOptions  map[string]interface{}  `json:"database_options"`
+	Retrieved        map[string]time.Time    `json:"resources_retrieved_at,omitempty"`
+	RetrievedAt      time.Time               `json:"retrieved_at"`
+}
+
+// Fetch retrieves every resource in the catalog, stopping at the first
+// error or when ctx is done.
+func Fetch(ctx context.Context, client *godo.Client) (*Catalog, error) {
+	c := &Catalog{}
+	for _, r := range Resources {
+		store, err := r.Fetch(ctx, client)
+		if err != nil {
+			return nil, err
+		}
+		store(c)
+	}
+
+	return c, nil
+}
+
+// ResourceRetrievedAt returns when the named resource was retrieved, and
+// false if it hasn't been. Snapshots saved before resources were retrieved
+// separately have every resource retrieved at RetrievedAt. c may be nil.
+func (c *Catalog) ResourceRetrievedAt(name string) (time.Time, bool) {
+	if c == nil {
+		return time.Time{}, false
+	}
+	if c.Retrieved == nil {
+		return c.RetrievedAt, !c.RetrievedAt.IsZero()
+	}
+	at, ok := c.Retrieved[name]
+
+	return at, ok
+}
+
+// Clone returns a copy of c that resources can be stored in without
+// affecting c. Cloning a nil catalog returns an empty one.
+func (c *Catalog) Clone() *Catalog {
+	clone := &Catalog{}
+	if c == nil {
+		return clone
+	}
+	*clone = *c
+	clone.Retrieved = make(map[string]time.Time, len(Resources))
+	for _, r := range Resources {
+		if at, ok := c.ResourceRetrievedAt(r.Name); ok {
+			clone.Retrieved[r.Name] = at
+		}
+	}
+
+	return clone
+}
+
+// Load reads a snapshot previously written by Save.
+func Load(path string) (*Catalog, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var c Catalog
+	if err := json.Unmarshal(b, &c); err != nil {
+		return nil, err
+	}
+
+	return &c, nil
+}
+
+// Save writes c to path as JSON. The snapshot is written to a temporary file
+// first and renamed into place, so readers never see a partial file.
+func Save(c *Catalog, path string) error {
+	return WriteJSON(path, c)
+}
+
+// WriteJSON writes v to path as indented JSON in the same way as Save. It
+// lets callers save a snapshot with extra fields alongside the catalog's,
+// which Load ignores.
+func WriteJSON(path string, v interface{}) error {
+	b, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	f, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+	if _, err := f.Write(b); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(f.Name(), path)
+}
+
+// Identifier converts a slug to a PascalCase identifier, e.g. "s-1vcpu-1gb"
+// becomes "S1vcpu1gb". Identifiers that would start with a digit are
+// prefixed with an underscore.
+func Identifier(slug string) string {
+	var b strings.Builder
+	upper := true
+	for _, r := range slug {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			upper = true
+			continue
+		}
+		if upper {
+			r = unicode.ToUpper(r)
+			upper = false
+		}
+		b.WriteRune(r)
+	}
+
+	ident := b.String()
+	if ident == "" || unicode.IsDigit(rune(ident[0])) {
+		ident = "_" + ident
+	}
+
+	return ident
+}
+
+// UniqueIdentifier returns ident, adding a numeric suffix if it has already
+// been used, and marks the result as used. Distinct slugs can map to the same
+// identifier, e.g. "a-b" and "a_b".
+func UniqueIdentifier(used map[string]bool, ident string) string {
+	candidate := ident
+	for n := 2; used[candidate]; n++ {
+		candidate = ident + strconv.Itoa(n)
+	}
+	used[candidate] = true
+
+	return candidate
+}
@@ -0,0 +1,111 @@
+package catalog
+
+import (
+	"testing"
+	"time"
+)
+
+func TestIdentifier(t *testing.T) {
+	tests := []struct {
+		slug string
+		want string
+	}{
+		{"s-1vcpu-1gb", "S1vcpu1gb"},
+		{"nyc1", "Nyc1"},
+		{"ubuntu-24-04-x64", "Ubuntu2404X64"},
+		{"1-click", "_1Click"},
+		{"", "_"},
+	}
+	for _, tt := range tests {
+		if got := Identifier(tt.slug); got != tt.want {
+			t.Errorf("Identifier(%q) = %q, want %q", tt.slug, got, tt.want)
+		}
+	}
+}
+
+func TestUniqueIdentifier(t *testing.T) {
+	tests := []struct {
+		name  string
+		slugs []string
+		want  []string
+	}{
+		{
+			name:  "distinct",
+			slugs: []string{"a", "b"},
+			want:  []string{"A", "B"},
+		},
+		{
+			name:  "colliding",
+			slugs: []string{"a-b", "a.b", "a_b"},
+			want:  []string{"AB", "AB2", "AB3"},
+		},
+		{
+			name:  "suffix already taken",
+			slugs: []string{"a-b2", "a-b", "a.b"},
+			want:  []string{"AB2", "AB", "AB3"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			used := make(map[string]bool)
+			for i, slug := range tt.slugs {
+				if got := UniqueIdentifier(used, Identifier(slug)); got != tt.want[i] {
+					t.Errorf("UniqueIdentifier(%q) = %q, want %q", slug, got, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestResourceRetrievedAt(t *testing.T) {
+	at := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name   string
+		cat    *Catalog
+		want   time.Time
+		wantOK bool
+	}{
+		{name: "nil catalog"},
+		{name: "empty catalog", cat: &Catalog{}},
+		{
+			name:   "retrieved",
+			cat:    &Catalog{Retrieved: map[string]time.Time{ResourceSizes: at}},
+			want:   at,
+			wantOK: true,
+		},
+		{
+			name: "not retrieved",
+			cat:  &Catalog{Retrieved: map[string]time.Time{ResourceRegions: at}, RetrievedAt: at},
+		},
+		{
+			name:   "older snapshot",
+			cat:    &Catalog{RetrievedAt: at},
+			want:   at,
+			wantOK: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := tt.cat.ResourceRetrievedAt(ResourceSizes)
+			if !got.Equal(tt.want) || ok != tt.wantOK {
+				t.Errorf("got %v, %v, want %v, %v", got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestCloneIsIndependent(t *testing.T) {
+	at := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	orig := &Catalog{RetrievedAt: at}
+
+	clone := orig.Clone()
+	clone.Retrieved[ResourceSizes] = at.Add(time.Hour)
+	if got, _ := orig.ResourceRetrievedAt(ResourceSizes); !got.Equal(at) {
+		t.Errorf("changing the clone changed the original's retrieval time to %v", got)
+	}
+	for _, r := range Resources {
+		if _, ok := clone.ResourceRetrievedAt(r.Name); !ok {
+			t.Errorf("clone of an older snapshot is missing %s", r.Name)
+		}
+	}
+}
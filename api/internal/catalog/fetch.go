@@ -0,0 +1,224 @@
+package catalog
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/digitalocean/godo"
+)
+
+// Names of the resources in the catalog, as used by /catalog and /readyz.
+const (
+	ResourceSizes            = "sizes"
+	ResourceRegions          = "regions"
+	ResourceAppImages        = "app_images"
+	ResourceDistroImages     = "distro_images"
+	ResourceK8sOptions       = "k8s_options"
+	ResourceAppInstanceSizes = "app_instance_sizes"
+	ResourceDatabaseOptions  = "database_options"
+)
+
+// Resource is one section of the catalog, retrieved independently of the
+// others so that one failing upstream call doesn't hold up the rest.
+type Resource struct {
+	Name string
+	// Fetch retrieves the resource, returning a function that stores it in
+	// a catalog along with the time it was retrieved.
+	Fetch func(ctx context.Context, client *godo.Client) (func(*Catalog), error)
+}
+
+// Resources lists every resource in the catalog, in the order Fetch
+// retrieves them.
+var Resources = []Resource{
+	resource(ResourceSizes, GetSizes, func(c *Catalog, v []godo.Size) { c.Sizes = v }),
+	resource(ResourceRegions, GetRegions, func(c *Catalog, v []godo.Region) { c.Regions = v }),
+	resource(ResourceAppImages, imagesOfType("apps"), func(c *Catalog, v []godo.Image) { c.AppImages = v }),
+	resource(ResourceDistroImages, imagesOfType("distros"), func(c *Catalog, v []godo.Image) { c.DistroImages = v }),
+	resource(ResourceK8sOptions, GetKubernetesOptions, func(c *Catalog, v *godo.KubernetesOptions) { c.K8sOptions = v }),
+	resource(ResourceAppInstanceSizes, GetAppInstanceSizes, func(c *Catalog, v []godo.AppInstanceSize) { c.AppInstanceSizes = v }),
+	resource(ResourceDatabaseOptions, GetDatabaseOptions, func(c *Catalog, v map[string]interface{}) { c.DatabaseOptions = v }),
+}
+
+// ResourceNames returns the names of every resource in the catalog.
+func ResourceNames() []string {
+	names := make([]string, len(Resources))
+	for i, r := range Resources {
+		names[i] = r.Name
+	}
+
+	return names
+}
+
+// resource describes the resource retrieved by get and stored in a catalog
+// by set.
+func resource[T any](name string, get func(context.Context, *godo.Client) (T, error), set func(*Catalog, T)) Resource {
+	return Resource{
+		Name: name,
+		Fetch: func(ctx context.Context, client *godo.Client) (func(*Catalog), error) {
+			v, err := get(ctx, client)
+			if err != nil {
+				return nil, err
+			}
+			at := time.Now().UTC()
+
+			return func(c *Catalog) {
+				set(c, v)
+				if c.Retrieved == nil {
+					c.Retrieved = make(map[string]time.Time)
+				}
+				c.Retrieved[name] = at
+				if at.After(c.RetrievedAt) {
+					c.RetrievedAt = at
+				}
+			}, nil
+		},
+	}
+}
+
+func imagesOfType(imageType string) func(context.Context, *godo.Client) ([]godo.Image, error) {
+	return func(ctx context.Context, client *godo.Client) ([]godo.Image, error) {
+		return GetImages(ctx, client, imageType)
+	}
+}
+
+// GetImages lists the public application images when imageType is "apps",
+// and the distribution images otherwise.
+func GetImages(ctx context.Context, client *godo.Client, imageType string) ([]godo.Image, error) {
+	list := []godo.Image{}
+	opt := &godo.ListOptions{PerPage: 200}
+	for {
+		var (
+			images []godo.Image
+			resp   *godo.Response
+			err    error
+		)
+		if imageType == "apps" {
+			images, resp, err = client.Images.ListApplication(ctx, opt)
+		} else {
+			images, resp, err = client.Images.ListDistribution(ctx, opt)
+		}
+
+		if err != nil {
+			return nil, err
+		}
+		for _, i := range images {
+			list = append(list, i)
+		}
+		if resp.Links == nil || resp.Links.IsLastPage() {
+			break
+		}
+		page, err := resp.Links.CurrentPage()
+		if err != nil {
+			return nil, err
+		}
+		opt.Page = page + 1
+	}
+
+	return list, nil
+}
+
+// GetKubernetesOptions returns the versions, regions and node sizes
+// available to Kubernetes clusters.
+func GetKubernetesOptions(ctx context.Context, client *godo.Client) (*godo.KubernetesOptions, error) {
+	options, _, err := client.Kubernetes.GetOptions(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return options, nil
+}
+
+// GetRegions lists all regions.
+func GetRegions(ctx context.Context, client *godo.Client) ([]godo.Region, error) {
+	list := []godo.Region{}
+	opt := &godo.ListOptions{PerPage: 200}
+	for {
+		regions, resp, err := client.Regions.List(ctx, opt)
+		if err != nil {
+			return nil, err
+		}
+		for _, r := range regions {
+			list = append(list, r)
+		}
+		if resp.Links == nil || resp.Links.IsLastPage() {
+			break
+		}
+		page, err := resp.Links.CurrentPage()
+		if err != nil {
+			return nil, err
+		}
+		opt.Page = page + 1
+	}
+
+	return list, nil
+}
+
+// GetSizes lists all Droplet sizes.
+func GetSizes(ctx context.Context, client *godo.Client) ([]godo.Size, error) {
+	list := []godo.Size{}
+	opt := &godo.ListOptions{PerPage: 200}
+	for {
+		sizes, resp, err := client.Sizes.List(ctx, opt)
+		if err != nil {
+			return nil, err
+		}
+		for _, s := range sizes {
+			list = append(list, s)
+		}
+		if resp.Links == nil || resp.Links.IsLastPage() {
+			break
+		}
+		page, err := resp.Links.CurrentPage()
+		if err != nil {
+			return nil, err
+		}
+		opt.Page = page + 1
+	}
+
+	return list, nil
+}
+
+// GetAppInstanceSizes lists the App Platform instance sizes.
+func GetAppInstanceSizes(ctx context.Context, client *godo.Client) ([]godo.AppInstanceSize, error) {
+	list := []godo.AppInstanceSize{}
+
+	// Get app instance sizes
+	sizes, _, err := client.Apps.ListInstanceSizes(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, s := range sizes {
+		list = append(list, *s)
+	}
+
+	return list, nil
+}
+
+// GetDatabaseOptions returns the options for each database engine as a
+// generic map keyed by engine.
+func GetDatabaseOptions(ctx context.Context, client *godo.Client) (map[string]interface{}, error) {
+	// Call the DatabaseOptions endpoint
+	options, _, err := client.Databases.ListOptions(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// Convert options to a map for flexibility
+	optionsMap := make(map[string]interface{})
+
+	// Convert from godo.DatabaseOptions to a map
+	optionsBytes, err := json.Marshal(options)
+	if err != nil {
+		return nil, err
+	}
+
+	// Unmarshal into a generic map
+	err = json.Unmarshal(optionsBytes, &optionsMap)
+	if err != nil {
+		return nil, err
+	}
+
+	return optionsMap, nil
+}
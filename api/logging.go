@@ -0,0 +1,120 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+)
+
+type requestInfoContextKey struct{}
+
+// requestInfo collects what a request did as it is served, for its access
+// log line.
+type requestInfo struct {
+	id string
+
+	mu         sync.Mutex
+	cache      string
+	upstreamID []string
+}
+
+// newLogger returns a JSON logger writing to stderr at the level named by
+// LOG_LEVEL (debug, info, warn or error; default info).
+func newLogger(level string) (*slog.Logger, error) {
+	var l slog.Level
+	if level != "" {
+		if err := l.UnmarshalText([]byte(level)); err != nil {
+			return nil, err
+		}
+	}
+
+	return slog.New(slog.NewJSONHandler(os.Stderr, &slog.HandlerOptions{Level: l})), nil
+}
+
+// fatal logs msg at error level and exits.
+func fatal(msg string, args ...any) {
+	slog.Error(msg, args...)
+	os.Exit(1)
+}
+
+// loggerFrom returns the default logger, with the request ID if ctx belongs
+// to a request.
+func loggerFrom(ctx context.Context) *slog.Logger {
+	if info := requestInfoFrom(ctx); info != nil && info.id != "" {
+		return slog.With("request_id", info.id)
+	}
+
+	return slog.Default()
+}
+
+func requestInfoFrom(ctx context.Context) *requestInfo {
+	info, _ := ctx.Value(requestInfoContextKey{}).(*requestInfo)
+	return info
+}
+
+// setCacheStatus records whether the request was served from the cached
+// catalog.
+func setCacheStatus(ctx context.Context, status string) {
+	if info := requestInfoFrom(ctx); info != nil {
+		info.mu.Lock()
+		info.cache = status
+		info.mu.Unlock()
+	}
+}
+
+// addUpstreamRequestID records the ID of a DigitalOcean API request made
+// while serving the request.
+func addUpstreamRequestID(ctx context.Context, id string) {
+	if info := requestInfoFrom(ctx); info != nil && id != "" {
+		info.mu.Lock()
+		info.upstreamID = append(info.upstreamID, id)
+		info.mu.Unlock()
+	}
+}
+
+// upstreamIDs returns the IDs of the DigitalOcean API requests recorded so
+// far.
+func (info *requestInfo) upstreamIDs() []string {
+	info.mu.Lock()
+	defer info.mu.Unlock()
+	return append([]string(nil), info.upstreamID...)
+}
+
+// withAccessLog logs every request once it has been served. Like
+// withMetrics, it must wrap the ServeMux directly so the route is known.
+func withAccessLog(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+
+		next.ServeHTTP(rec, r)
+
+		info := requestInfoFrom(r.Context())
+		attrs := []any{
+			"method", r.Method,
+			"path", r.URL.Path,
+			"route", r.Pattern,
+			"status", rec.status,
+			"duration_ms", durationMillis(time.Since(start)),
+		}
+		if info != nil {
+			info.mu.Lock()
+			if info.cache != "" {
+				attrs = append(attrs, "cache", info.cache)
+			}
+			if len(info.upstreamID) > 0 {
+				attrs = append(attrs, "upstream_request_ids", info.upstreamID)
+			}
+			info.mu.Unlock()
+		}
+		loggerFrom(r.Context()).Info("request", attrs...)
+	})
+}
+
+// durationMillis returns d in fractional milliseconds, for log fields.
+func durationMillis(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
@@ -3,17 +3,39 @@ package main
 import (
 	"context"
 	"encoding/json"
-	"log"
+	"errors"
+	"log/slog"
+	"net"
 	"net/http"
 	"os"
+	"os/signal"
 	"path"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
 	"time"
 
+	"github.com/andrewsomething/do-api-slugs/api/internal/catalog"
+	slugsv1 "github.com/andrewsomething/do-api-slugs/api/proto/slugs/v1"
 	"github.com/digitalocean/godo"
+	"github.com/graphql-go/graphql"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/reflection"
 )
 
 const (
 	defaultPort = "3000"
+
+	defaultReadHeaderTimeout = 5 * time.Second
+	defaultReadTimeout       = 30 * time.Second
+	// defaultWriteTimeout leaves room for REQUEST_TIMEOUT. Streaming
+	// responses clear their own write deadline.
+	defaultWriteTimeout    = time.Minute
+	defaultIdleTimeout     = 2 * time.Minute
+	defaultShutdownTimeout = 25 * time.Second
 )
 
 type imageResponse struct {
@@ -47,13 +69,33 @@ type databaseOptionsResponse struct {
 }
 
 type handler struct {
-	client *godo.Client
+	client    *godo.Client
+	refresher *refresher
+	notifier  *notifier
+	prices    *priceTracker
+	removals  *removalTracker
+	retrier   *retrier
+
+	// requestTimeout bounds the upstream calls made for a single request.
+	requestTimeout time.Duration
+	graphQLSchema  graphql.Schema
+
+	// stopping is closed when the server starts shutting down, to end
+	// streams that would otherwise hold it open.
+	stopping     chan struct{}
+	stoppingOnce sync.Once
 }
 
 func main() {
+	logger, err := newLogger(os.Getenv("LOG_LEVEL"))
+	if err != nil {
+		fatal("invalid LOG_LEVEL", "error", err)
+	}
+	slog.SetDefault(logger)
+
 	token := os.Getenv("DO_TOKEN")
 	if token == "" {
-		log.Fatal("DigitalOcean API token not configured")
+		fatal("DigitalOcean API token not configured")
 	}
 
 	port := os.Getenv("PORT")
@@ -61,14 +103,72 @@ func main() {
 		port = defaultPort
 	}
 
+	grpcPort := os.Getenv("GRPC_PORT")
+	if grpcPort == "" {
+		grpcPort = defaultGRPCPort
+	}
+
+	refreshInterval := durationEnv("REFRESH_INTERVAL", defaultRefreshInterval)
+	upstreamTimeout := durationEnv("UPSTREAM_TIMEOUT", defaultUpstreamTimeout)
+	requestTimeout := durationEnv("REQUEST_TIMEOUT", defaultRequestTimeout)
+	snapshotPath := os.Getenv("SNAPSHOT_PATH")
+	shutdownTimeout := durationEnv("SHUTDOWN_TIMEOUT", defaultShutdownTimeout)
+	retrier := newRetrier(
+		intEnv("UPSTREAM_RETRY_MAX", defaultRetryMax),
+		durationEnv("UPSTREAM_RETRY_WAIT_MIN", defaultRetryWaitMin),
+		durationEnv("UPSTREAM_RETRY_WAIT_MAX", defaultRetryWaitMax),
+	)
+
+	var webhookURLs []string
+	for _, u := range strings.Split(os.Getenv("WEBHOOK_URLS"), ",") {
+		if u = strings.TrimSpace(u); u != "" {
+			webhookURLs = append(webhookURLs, u)
+		}
+	}
+
+	graphQLSchema, err := newGraphQLSchema()
+	if err != nil {
+		fatal("invalid GraphQL schema", "error", err)
+	}
+
+	client, err := newClient(token, upstreamTimeout, retrier)
+	if err != nil {
+		fatal("unable to create DigitalOcean API client", "error", err)
+	}
+	refresher := newRefresher(client, refreshInterval)
+	prometheus.MustRegister(catalogCollector{refresher: refresher})
+
 	mux := http.NewServeMux()
 	handler := &handler{
-		client: godo.NewFromToken(token),
+		client:    client,
+		refresher: refresher,
+		notifier:  newNotifier(webhookURLs, os.Getenv("WEBHOOK_SECRET")),
+		prices:    newPriceTracker(),
+		removals:  newRemovalTracker(),
+		retrier:   retrier,
+
+		requestTimeout: requestTimeout,
+		graphQLSchema:  graphQLSchema,
+		stopping:       make(chan struct{}),
 	}
+	refresher.onRefresh(handler.notifier.notify)
+	refresher.onRefresh(handler.prices.record)
+	refresher.onRefresh(handler.removals.record)
+	if snapshotPath != "" {
+		handler.loadSnapshot(snapshotPath)
+		refresher.onRefresh(handler.snapshotSaver(snapshotPath))
+	}
+	go refresher.run()
 
 	notFoundHandler := http.HandlerFunc(handler.notFound)
 	mux.Handle("/", notFoundHandler)
 
+	healthzHandler := http.HandlerFunc(handler.healthz)
+	mux.HandleFunc("/healthz", healthzHandler)
+
+	readyzHandler := http.HandlerFunc(handler.readyz)
+	mux.HandleFunc("/readyz", readyzHandler)
+
 	imagesHandler := http.HandlerFunc(handler.images)
 	mux.Handle("/images/apps", imagesHandler)
 	mux.Handle("/images/distros", imagesHandler)
@@ -82,268 +182,261 @@ func main() {
 	sizeHandler := http.HandlerFunc(handler.sizes)
 	mux.HandleFunc("/sizes", sizeHandler)
 
+	gpuSizeHandler := http.HandlerFunc(handler.gpuSizes)
+	mux.HandleFunc("/sizes/gpu", gpuSizeHandler)
+
+	sizeAlternativesHandler := http.HandlerFunc(handler.sizeAlternatives)
+	mux.HandleFunc("/sizes/{slug}/alternatives", sizeAlternativesHandler)
+
 	appInstanceSizeHandler := http.HandlerFunc(handler.appInstanceSizes)
 	mux.HandleFunc("/apps/tiers/instance_sizes", appInstanceSizeHandler)
 
 	dbOptionsHandler := http.HandlerFunc(handler.databaseOptions)
 	mux.HandleFunc("/databases/options", dbOptionsHandler)
 
-	log.Printf("Listening on :%s", port)
-	log.Fatal(http.ListenAndServe(":"+port, mux))
+	catalogHandler := http.HandlerFunc(handler.aggregateCatalog)
+	mux.HandleFunc("/catalog", catalogHandler)
+
+	dbSizesHandler := http.HandlerFunc(handler.databaseSizes)
+	mux.HandleFunc("/databases/sizes", dbSizesHandler)
+
+	webhookDeliveriesHandler := http.HandlerFunc(handler.webhookDeliveries)
+	mux.HandleFunc("/webhooks/deliveries", webhookDeliveriesHandler)
+
+	upstreamStatsHandler := http.HandlerFunc(handler.upstreamStats)
+	mux.HandleFunc("/upstream/stats", upstreamStatsHandler)
+
+	priceHistoryHandler := http.HandlerFunc(handler.priceHistory)
+	mux.HandleFunc("/prices/{slug}/history", priceHistoryHandler)
+
+	priceChangesHandler := http.HandlerFunc(handler.priceChanges)
+	mux.HandleFunc("/prices/changes", priceChangesHandler)
+
+	deprecationsHandler := http.HandlerFunc(handler.deprecations)
+	mux.HandleFunc("/deprecations", deprecationsHandler)
+
+	validateHandler := http.HandlerFunc(handler.validate)
+	mux.HandleFunc("/validate", validateHandler)
+
+	estimateHandler := http.HandlerFunc(handler.estimate)
+	mux.HandleFunc("/estimate", estimateHandler)
+
+	recommendSizesHandler := http.HandlerFunc(handler.recommendSizes)
+	mux.HandleFunc("/recommend/sizes", recommendSizesHandler)
+
+	terraformHandler := http.HandlerFunc(handler.exportTerraform)
+	mux.HandleFunc("/export/terraform", terraformHandler)
+
+	pulumiHandler := http.HandlerFunc(handler.exportPulumi)
+	mux.HandleFunc("/export/pulumi", pulumiHandler)
+
+	openAPIHandler := http.HandlerFunc(handler.openAPI)
+	mux.HandleFunc("/openapi.json", openAPIHandler)
+
+	graphQLHandler := http.HandlerFunc(handler.graphQL)
+	mux.HandleFunc("/graphql", graphQLHandler)
+
+	eventsHandler := http.HandlerFunc(handler.events)
+	mux.HandleFunc("/events", eventsHandler)
+
+	mux.Handle("/metrics", promhttp.Handler())
+
+	grpcListener, err := net.Listen("tcp", ":"+grpcPort)
+	if err != nil {
+		fatal("unable to listen for gRPC", "error", err)
+	}
+	grpcSrv := grpc.NewServer()
+	slugsv1.RegisterSlugsServiceServer(grpcSrv, &grpcServer{h: handler})
+	reflection.Register(grpcSrv)
+	go func() {
+		slog.Info("serving gRPC", "port", grpcPort)
+		// Serve returns nil once the server is stopped on shutdown.
+		if err := grpcSrv.Serve(grpcListener); err != nil {
+			fatal("gRPC server stopped", "error", err)
+		}
+	}()
+
+	srv := &http.Server{
+		Addr:              ":" + port,
+		Handler:           withRequestID(withAccessLog(withMetrics(mux))),
+		ReadHeaderTimeout: durationEnv("HTTP_READ_HEADER_TIMEOUT", defaultReadHeaderTimeout),
+		ReadTimeout:       durationEnv("HTTP_READ_TIMEOUT", defaultReadTimeout),
+		WriteTimeout:      durationEnv("HTTP_WRITE_TIMEOUT", defaultWriteTimeout),
+		IdleTimeout:       durationEnv("HTTP_IDLE_TIMEOUT", defaultIdleTimeout),
+	}
+	go func() {
+		slog.Info("listening", "port", port)
+		if err := srv.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+			fatal("HTTP server stopped", "error", err)
+		}
+	}()
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGTERM, os.Interrupt)
+	<-ctx.Done()
+	stop()
+
+	// Stop accepting requests and let those in flight finish, then save the
+	// catalog so the next start can serve it straight away.
+	slog.Info("shutting down")
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	handler.stopStreams()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		slog.Error("HTTP server did not shut down cleanly", "error", err)
+	}
+	stopGRPC(shutdownCtx, grpcSrv)
+	if snapshotPath != "" {
+		handler.flushSnapshot(snapshotPath)
+	}
+	slog.Info("stopped")
+}
+
+// stopStreams ends /events and WatchChanges streams.
+func (h *handler) stopStreams() {
+	h.stoppingOnce.Do(func() { close(h.stopping) })
+}
+
+// durationEnv parses the duration in the named environment variable,
+// returning def if it is unset.
+func durationEnv(name string, def time.Duration) time.Duration {
+	v := os.Getenv(name)
+	if v == "" {
+		return def
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil {
+		fatal("invalid "+name, "error", err)
+	}
+
+	return d
+}
+
+// intEnv parses the integer in the named environment variable, returning def
+// if it is unset.
+func intEnv(name string, def int) int {
+	v := os.Getenv(name)
+	if v == "" {
+		return def
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		fatal("invalid "+name, "error", err)
+	}
+
+	return n
 }
 
 func writeJSONResponse(w http.ResponseWriter, v interface{}) {
 	w.Header().Set("Content-Type", "application/json")
-	w.Header().Set("Cache-Control", "s-maxage=3600, maxage=0")
+	setCacheControl(w)
 	json.NewEncoder(w).Encode(v)
 }
 
-func writeJSONError(w http.ResponseWriter, code int) {
-	msg := map[string]string{
-		"error": http.StatusText(code),
+// setCacheControl allows the CDN to cache the response unless the handler
+// has already chosen a policy.
+func setCacheControl(w http.ResponseWriter) {
+	if w.Header().Get("Cache-Control") == "" {
+		w.Header().Set("Cache-Control", "s-maxage=3600, maxage=0")
 	}
-
-	w.WriteHeader(code)
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(msg)
 }
 
 func (h *handler) notFound(w http.ResponseWriter, r *http.Request) {
 	writeJSONError(w, http.StatusNotFound)
 }
 
+// The resource handlers serve the cached catalog, like gRPC and GraphQL, so
+// every API returns the same data. Only /catalog calls upstream per request.
+// Each resource is cached separately, so retrieved_at is when that resource
+// was retrieved.
+
 func (h *handler) images(w http.ResponseWriter, r *http.Request) {
-	imageType := path.Base(r.URL.Path)
-	images, err := getImages(h.client, imageType)
+	resource := catalog.ResourceAppImages
+	if path.Base(r.URL.Path) == "distros" {
+		resource = catalog.ResourceDistroImages
+	}
+	cat, err := h.catalogFor(r.Context(), resource)
 	if err != nil {
-		log.Println(err.Error())
-		writeJSONError(w, http.StatusInternalServerError)
+		h.writeUpstreamError(w, r, err)
 		return
 	}
-	timestamp := time.Now().Format("Mon Jan _2 15:04:05 2006 UTC")
+	images := cat.AppImages
+	if resource == catalog.ResourceDistroImages {
+		images = cat.DistroImages
+	}
 	resp := imageResponse{
 		Images:      images,
-		RetrievedAt: timestamp,
-	}
-
-	writeJSONResponse(w, resp)
-}
-
-func getImages(client *godo.Client, imageType string) ([]godo.Image, error) {
-	ctx := context.TODO()
-	list := []godo.Image{}
-	opt := &godo.ListOptions{PerPage: 200}
-	for {
-		var (
-			images []godo.Image
-			resp   *godo.Response
-			err    error
-		)
-		if imageType == "apps" {
-			images, resp, err = client.Images.ListApplication(ctx, opt)
-		} else {
-			images, resp, err = client.Images.ListDistribution(ctx, opt)
-		}
-
-		if err != nil {
-			return nil, err
-		}
-		for _, i := range images {
-			list = append(list, i)
-		}
-		if resp.Links == nil || resp.Links.IsLastPage() {
-			break
-		}
-		page, err := resp.Links.CurrentPage()
-		if err != nil {
-			return nil, err
-		}
-		opt.Page = page + 1
+		RetrievedAt: retrievedAt(cat, resource),
 	}
 
-	return list, nil
+	writeResponse(w, r, resp)
 }
 
 func (h *handler) k8s(w http.ResponseWriter, r *http.Request) {
-	options, err := getOptions(h.client)
+	cat, err := h.catalogFor(r.Context(), catalog.ResourceK8sOptions)
 	if err != nil {
-		log.Println(err.Error())
-		writeJSONError(w, http.StatusInternalServerError)
+		h.writeUpstreamError(w, r, err)
 		return
 	}
-	timestamp := time.Now().Format("Mon Jan _2 15:04:05 2006 UTC")
 	resp := k8sResponse{
-		Options:     options,
-		RetrievedAt: timestamp,
+		Options:     cat.K8sOptions,
+		RetrievedAt: retrievedAt(cat, catalog.ResourceK8sOptions),
 	}
 
-	writeJSONResponse(w, resp)
-}
-
-func getOptions(client *godo.Client) (*godo.KubernetesOptions, error) {
-	ctx := context.TODO()
-	options, _, err := client.Kubernetes.GetOptions(ctx)
-	if err != nil {
-		return nil, err
-	}
-
-	return options, nil
+	writeResponse(w, r, resp)
 }
 
 func (h *handler) regions(w http.ResponseWriter, r *http.Request) {
-	regions, err := getRegions(h.client)
+	cat, err := h.catalogFor(r.Context(), catalog.ResourceRegions)
 	if err != nil {
-		log.Println(err.Error())
-		writeJSONError(w, http.StatusInternalServerError)
+		h.writeUpstreamError(w, r, err)
 		return
 	}
-	timestamp := time.Now().Format("Mon Jan _2 15:04:05 2006 UTC")
 	resp := regionsResponse{
-		Regions:     regions,
-		RetrievedAt: timestamp,
-	}
-
-	writeJSONResponse(w, resp)
-}
-
-func getRegions(client *godo.Client) ([]godo.Region, error) {
-	ctx := context.TODO()
-	list := []godo.Region{}
-	opt := &godo.ListOptions{PerPage: 200}
-	for {
-		regions, resp, err := client.Regions.List(ctx, opt)
-		if err != nil {
-			return nil, err
-		}
-		for _, r := range regions {
-			list = append(list, r)
-		}
-		if resp.Links == nil || resp.Links.IsLastPage() {
-			break
-		}
-		page, err := resp.Links.CurrentPage()
-		if err != nil {
-			return nil, err
-		}
-		opt.Page = page + 1
+		Regions:     cat.Regions,
+		RetrievedAt: retrievedAt(cat, catalog.ResourceRegions),
 	}
 
-	return list, nil
+	writeResponse(w, r, resp)
 }
 
 func (h *handler) sizes(w http.ResponseWriter, r *http.Request) {
-	sizes, err := getSizes(h.client)
+	cat, err := h.catalogFor(r.Context(), catalog.ResourceSizes)
 	if err != nil {
-		log.Println(err.Error())
-		writeJSONError(w, http.StatusInternalServerError)
+		h.writeUpstreamError(w, r, err)
 		return
 	}
-	timestamp := time.Now().Format("Mon Jan _2 15:04:05 2006 UTC")
 	resp := sizesResponse{
-		Sizes:       sizes,
-		RetrievedAt: timestamp,
+		Sizes:       cat.Sizes,
+		RetrievedAt: retrievedAt(cat, catalog.ResourceSizes),
 	}
 
-	writeJSONResponse(w, resp)
-}
-
-func getSizes(client *godo.Client) ([]godo.Size, error) {
-	ctx := context.TODO()
-	list := []godo.Size{}
-	opt := &godo.ListOptions{PerPage: 200}
-	for {
-		sizes, resp, err := client.Sizes.List(ctx, opt)
-		if err != nil {
-			return nil, err
-		}
-		for _, s := range sizes {
-			list = append(list, s)
-		}
-		if resp.Links == nil || resp.Links.IsLastPage() {
-			break
-		}
-		page, err := resp.Links.CurrentPage()
-		if err != nil {
-			return nil, err
-		}
-		opt.Page = page + 1
-	}
-
-	return list, nil
+	writeResponse(w, r, resp)
 }
 
 func (h *handler) appInstanceSizes(w http.ResponseWriter, r *http.Request) {
-	sizes, err := getAppInstanceSizes(h.client)
+	cat, err := h.catalogFor(r.Context(), catalog.ResourceAppInstanceSizes)
 	if err != nil {
-		log.Println(err.Error())
-		writeJSONError(w, http.StatusInternalServerError)
+		h.writeUpstreamError(w, r, err)
 		return
 	}
-	timestamp := time.Now().Format("Mon Jan _2 15:04:05 2006 UTC")
 	resp := appInstanceSizesResponse{
-		Sizes:       sizes,
-		RetrievedAt: timestamp,
-	}
-
-	writeJSONResponse(w, resp)
-}
-
-func getAppInstanceSizes(client *godo.Client) ([]godo.AppInstanceSize, error) {
-	ctx := context.TODO()
-	list := []godo.AppInstanceSize{}
-
-	// Get app instance sizes
-	sizes, _, err := client.Apps.ListInstanceSizes(ctx)
-	if err != nil {
-		return nil, err
-	}
-
-	for _, s := range sizes {
-		list = append(list, *s)
+		Sizes:       cat.AppInstanceSizes,
+		RetrievedAt: retrievedAt(cat, catalog.ResourceAppInstanceSizes),
 	}
 
-	return list, nil
+	writeResponse(w, r, resp)
 }
 
 func (h *handler) databaseOptions(w http.ResponseWriter, r *http.Request) {
-	options, err := getDatabaseOptions(h.client)
+	cat, err := h.catalogFor(r.Context(), catalog.ResourceDatabaseOptions)
 	if err != nil {
-		log.Println(err.Error())
-		writeJSONError(w, http.StatusInternalServerError)
+		h.writeUpstreamError(w, r, err)
 		return
 	}
-	timestamp := time.Now().Format("Mon Jan _2 15:04:05 2006 UTC")
 	resp := databaseOptionsResponse{
-		Options:     options,
-		RetrievedAt: timestamp,
-	}
-
-	writeJSONResponse(w, resp)
-}
-
-func getDatabaseOptions(client *godo.Client) (map[string]interface{}, error) {
-	ctx := context.TODO()
-
-	// Call the DatabaseOptions endpoint
-	options, _, err := client.Databases.ListOptions(ctx)
-	if err != nil {
-		return nil, err
-	}
-
-	// Convert options to a map for flexibility
-	optionsMap := make(map[string]interface{})
-
-	// Convert from godo.DatabaseOptions to a map
-	optionsBytes, err := json.Marshal(options)
-	if err != nil {
-		return nil, err
-	}
-
-	// Unmarshal into a generic map
-	err = json.Unmarshal(optionsBytes, &optionsMap)
-	if err != nil {
-		return nil, err
+		Options:     cat.DatabaseOptions,
+		RetrievedAt: retrievedAt(cat, catalog.ResourceDatabaseOptions),
 	}
 
-	return optionsMap, nil
+	writeResponse(w, r, resp)
 }
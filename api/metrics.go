@@ -0,0 +1,226 @@
+package main
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/andrewsomething/do-api-slugs/api/internal/catalog"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+const (
+	metricsNamespace = "slugs"
+
+	upstreamRequestIDHeader = "X-Request-Id"
+)
+
+var (
+	httpRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Name:      "http_requests_total",
+		Help:      "HTTP requests served, by route, method and status code.",
+	}, []string{"route", "method", "code"})
+	httpRequestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: metricsNamespace,
+		Name:      "http_request_duration_seconds",
+		Help:      "Time taken to serve HTTP requests, by route and method.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"route", "method"})
+
+	upstreamRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Name:      "upstream_requests_total",
+		Help:      "Calls to the DigitalOcean API, by godo method and status code.",
+	}, []string{"method", "code"})
+	upstreamErrors = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Name:      "upstream_errors_total",
+		Help:      "Calls to the DigitalOcean API that failed or returned an error status, by godo method.",
+	}, []string{"method"})
+	upstreamRequestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: metricsNamespace,
+		Name:      "upstream_request_duration_seconds",
+		Help:      "Time taken by calls to the DigitalOcean API, including retries, by godo method.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method"})
+	upstreamRetries = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Name:      "upstream_retries_total",
+		Help:      "Retried calls to the DigitalOcean API, by status code or \"error\".",
+	}, []string{"reason"})
+	upstreamRetriesExhausted = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Name:      "upstream_retries_exhausted_total",
+		Help:      "Calls to the DigitalOcean API that failed after every retry.",
+	})
+
+	catalogCacheRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Name:      "catalog_cache_requests_total",
+		Help:      "Lookups of cached resources, by resource and result (hit or miss).",
+	}, []string{"resource", "result"})
+)
+
+var (
+	catalogItemsDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(metricsNamespace, "catalog", "items"),
+		"Items in the cached catalog, by resource.",
+		[]string{"resource"}, nil,
+	)
+	catalogLastRefreshDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(metricsNamespace, "catalog", "last_refresh_timestamp_seconds"),
+		"Time each cached resource was last retrieved, by resource.",
+		[]string{"resource"}, nil,
+	)
+	catalogAgeDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(metricsNamespace, "catalog", "age_seconds"),
+		"Age of each cached resource, by resource.",
+		[]string{"resource"}, nil,
+	)
+)
+
+// catalogCollector reports the size and staleness of each cached resource at
+// scrape time. Nothing is reported for a resource until it has been
+// retrieved.
+type catalogCollector struct {
+	refresher *refresher
+}
+
+func (c catalogCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- catalogItemsDesc
+	ch <- catalogLastRefreshDesc
+	ch <- catalogAgeDesc
+}
+
+func (c catalogCollector) Collect(ch chan<- prometheus.Metric) {
+	counts := catalogItemCounts(c.refresher.latest())
+	for _, status := range c.refresher.status() {
+		if status.RetrievedAt.IsZero() {
+			continue
+		}
+		refreshed := float64(status.RetrievedAt.UnixNano()) / float64(time.Second)
+		ch <- prometheus.MustNewConstMetric(catalogLastRefreshDesc, prometheus.GaugeValue, refreshed, status.Name)
+		ch <- prometheus.MustNewConstMetric(catalogAgeDesc, prometheus.GaugeValue, time.Since(status.RetrievedAt).Seconds(), status.Name)
+		ch <- prometheus.MustNewConstMetric(catalogItemsDesc, prometheus.GaugeValue, float64(counts[status.Name]), status.Name)
+	}
+}
+
+// catalogItemCounts returns the number of items of each resource in cat,
+// keyed by resource name.
+func catalogItemCounts(cat *catalog.Catalog) map[string]int {
+	if cat == nil {
+		return nil
+	}
+	k8sVersions := 0
+	if cat.K8sOptions != nil {
+		k8sVersions = len(cat.K8sOptions.Versions)
+	}
+
+	return map[string]int{
+		catalog.ResourceSizes:            len(cat.Sizes),
+		catalog.ResourceRegions:          len(cat.Regions),
+		catalog.ResourceAppImages:        len(cat.AppImages),
+		catalog.ResourceDistroImages:     len(cat.DistroImages),
+		catalog.ResourceK8sOptions:       k8sVersions,
+		catalog.ResourceAppInstanceSizes: len(cat.AppInstanceSizes),
+		catalog.ResourceDatabaseOptions:  len(cat.DatabaseOptions),
+	}
+}
+
+// withMetrics records the count and latency of requests by route. It must
+// wrap the ServeMux directly, as the route is read from the request after
+// the mux has matched it.
+func withMetrics(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+
+		next.ServeHTTP(rec, r)
+
+		route := r.Pattern
+		if route == "" {
+			route = "none"
+		}
+		method := metricsMethod(r.Method)
+		httpRequests.WithLabelValues(route, method, strconv.Itoa(rec.status)).Inc()
+		httpRequestDuration.WithLabelValues(route, method).Observe(time.Since(start).Seconds())
+	})
+}
+
+// metricsMethod limits the method label to the methods the API serves, so
+// clients can't create arbitrarily many series.
+func metricsMethod(method string) string {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodPost, http.MethodOptions:
+		return method
+	default:
+		return "other"
+	}
+}
+
+// upstreamTransport records the count, latency and errors of calls to the
+// DigitalOcean API by the godo method that made them, and logs each call
+// with its DigitalOcean request ID at debug level.
+type upstreamTransport struct {
+	next http.RoundTripper
+}
+
+func (t *upstreamTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	method := upstreamMethod(req)
+	start := time.Now()
+	resp, err := t.next.RoundTrip(req)
+	upstreamRequestDuration.WithLabelValues(method).Observe(time.Since(start).Seconds())
+
+	code := "error"
+	if err == nil {
+		code = strconv.Itoa(resp.StatusCode)
+	}
+	upstreamRequests.WithLabelValues(method, code).Inc()
+	if err != nil || resp.StatusCode >= http.StatusBadRequest {
+		upstreamErrors.WithLabelValues(method).Inc()
+	}
+
+	logger := loggerFrom(req.Context())
+	if err != nil {
+		logger.Debug("upstream request", "method", method, "error", err, "duration_ms", durationMillis(time.Since(start)))
+		return resp, err
+	}
+	upstreamID := resp.Header.Get(upstreamRequestIDHeader)
+	addUpstreamRequestID(req.Context(), upstreamID)
+	logger.Debug("upstream request",
+		"method", method,
+		"status", resp.StatusCode,
+		"duration_ms", durationMillis(time.Since(start)),
+		"upstream_request_id", upstreamID,
+	)
+
+	return resp, err
+}
+
+// upstreamMethod names the godo method that made req, from its path.
+func upstreamMethod(req *http.Request) string {
+	switch req.URL.Path {
+	case "/v2/sizes":
+		return "Sizes.List"
+	case "/v2/regions":
+		return "Regions.List"
+	case "/v2/images":
+		switch req.URL.Query().Get("type") {
+		case "application":
+			return "Images.ListApplication"
+		case "distribution":
+			return "Images.ListDistribution"
+		}
+		return "Images.List"
+	case "/v2/kubernetes/options":
+		return "Kubernetes.GetOptions"
+	case "/v2/apps/tiers/instance_sizes":
+		return "Apps.ListInstanceSizes"
+	case "/v2/databases/options":
+		return "Databases.ListOptions"
+	default:
+		return "other"
+	}
+}
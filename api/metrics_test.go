@@ -0,0 +1,98 @@
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/andrewsomething/do-api-slugs/api/internal/catalog"
+	"github.com/digitalocean/godo"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+)
+
+// gatherByResource returns the value of each series of the named metric in
+// g, keyed by its resource label and then by its result label, if any.
+func gatherByResource(t *testing.T, g prometheus.Gatherer, name string) map[string]map[string]float64 {
+	t.Helper()
+	families, err := g.Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	values := make(map[string]map[string]float64)
+	for _, f := range families {
+		if f.GetName() != name {
+			continue
+		}
+		for _, m := range f.GetMetric() {
+			labels := make(map[string]string)
+			for _, l := range m.GetLabel() {
+				labels[l.GetName()] = l.GetValue()
+			}
+			if values[labels["resource"]] == nil {
+				values[labels["resource"]] = make(map[string]float64)
+			}
+			values[labels["resource"]][labels["result"]] = metricValue(m)
+		}
+	}
+
+	return values
+}
+
+func metricValue(m *dto.Metric) float64 {
+	if m.GetCounter() != nil {
+		return m.GetCounter().GetValue()
+	}
+
+	return m.GetGauge().GetValue()
+}
+
+func TestCatalogCollectorReportsEachResource(t *testing.T) {
+	sizesAt := time.Now().Add(-time.Hour)
+	regionsAt := time.Now().Add(-time.Minute)
+	r := newRefresher(nil, time.Minute)
+	r.seed(&catalog.Catalog{
+		Sizes:   []godo.Size{{Slug: "s-1vcpu-1gb"}, {Slug: "s-2vcpu-2gb"}},
+		Regions: []godo.Region{{Slug: "nyc1"}},
+		Retrieved: map[string]time.Time{
+			catalog.ResourceSizes:   sizesAt,
+			catalog.ResourceRegions: regionsAt,
+		},
+	})
+	reg := prometheus.NewRegistry()
+	reg.MustRegister(catalogCollector{refresher: r})
+
+	items := gatherByResource(t, reg, "slugs_catalog_items")
+	if len(items) != 2 || items[catalog.ResourceSizes][""] != 2 || items[catalog.ResourceRegions][""] != 1 {
+		t.Errorf("got items %v, want 2 sizes and 1 region only", items)
+	}
+	refreshed := gatherByResource(t, reg, "slugs_catalog_last_refresh_timestamp_seconds")
+	if got := refreshed[catalog.ResourceSizes][""]; got != float64(sizesAt.UnixNano())/float64(time.Second) {
+		t.Errorf("got sizes refreshed at %v, want %v", got, sizesAt)
+	}
+	ages := gatherByResource(t, reg, "slugs_catalog_age_seconds")
+	if sizes, regions := ages[catalog.ResourceSizes][""], ages[catalog.ResourceRegions][""]; sizes < 3600 || regions >= sizes {
+		t.Errorf("got sizes age %v and regions age %v, want each resource's own age", sizes, regions)
+	}
+}
+
+func TestCatalogForCountsLookupsPerResource(t *testing.T) {
+	h := &handler{refresher: newRefresher(nil, time.Minute)}
+	h.refresher.seed(&catalog.Catalog{
+		Retrieved: map[string]time.Time{catalog.ResourceSizes: time.Now()},
+	})
+
+	before := gatherByResource(t, prometheus.DefaultGatherer, "slugs_catalog_cache_requests_total")
+	if _, err := h.catalogFor(context.Background(), catalog.ResourceSizes); err != nil {
+		t.Fatal(err)
+	}
+	after := gatherByResource(t, prometheus.DefaultGatherer, "slugs_catalog_cache_requests_total")
+
+	if got := after[catalog.ResourceSizes]["hit"] - before[catalog.ResourceSizes]["hit"]; got != 1 {
+		t.Errorf("got %v sizes hits, want 1", got)
+	}
+	if got := after[catalog.ResourceRegions]["hit"] - before[catalog.ResourceRegions]["hit"]; got != 0 {
+		t.Errorf("got %v regions hits, want none", got)
+	}
+}
@@ -0,0 +1,382 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/graphql-go/graphql"
+)
+
+const (
+	openAPIVersion = "3.0.3"
+	apiVersion     = "1.0.0"
+)
+
+// apiParameter describes a query or path parameter of an operation.
+type apiParameter struct {
+	name        string
+	in          string
+	description string
+	schema      map[string]interface{}
+	required    bool
+}
+
+// apiOperation describes a route for the OpenAPI document. Request and
+// response are zero values of the Go types used by the handler; their
+// schemas are generated by reflection. Responses are available in every
+// format unless mediaType is set. A nil response is served as a string of
+// mediaType, or plain text.
+type apiOperation struct {
+	method    string
+	path      string
+	summary   string
+	params    []apiParameter
+	request   interface{}
+	response  interface{}
+	mediaType string
+}
+
+var (
+	regionParameter = apiParameter{
+		name:        "region",
+		in:          "query",
+		description: "Only include resources available in this region.",
+		schema:      schemaRef("RegionSlug"),
+	}
+	limitParameter = apiParameter{
+		name:        "limit",
+		in:          "query",
+		description: "Maximum number of results.",
+		schema:      map[string]interface{}{"type": "integer", "minimum": 1},
+	}
+)
+
+var apiOperations = []apiOperation{
+	{method: http.MethodGet, path: "/healthz", summary: "Check that the API is up", response: healthResponse{}},
+	{method: http.MethodGet, path: "/readyz", summary: "Check that every resource can be served, with the freshness of each", response: healthResponse{}},
+	{method: http.MethodGet, path: "/images/apps", summary: "List 1-Click application images", response: imageResponse{}},
+	{method: http.MethodGet, path: "/images/distros", summary: "List distribution images", response: imageResponse{}},
+	{method: http.MethodGet, path: "/regions", summary: "List regions", response: regionsResponse{}},
+	{method: http.MethodGet, path: "/k8s", summary: "List Kubernetes versions, regions and node sizes", response: k8sResponse{}},
+	{method: http.MethodGet, path: "/sizes", summary: "List Droplet sizes", response: sizesResponse{}},
+	{method: http.MethodGet, path: "/sizes/gpu", summary: "List GPU Droplet sizes", response: gpuSizesResponse{}},
+	{
+		method:  http.MethodGet,
+		path:    "/sizes/{slug}/alternatives",
+		summary: "List the sizes most similar to a Droplet size",
+		params: []apiParameter{
+			{name: "slug", in: "path", required: true, schema: schemaRef("SizeSlug")},
+			regionParameter,
+			limitParameter,
+		},
+		response: sizeAlternativesResponse{},
+	},
+	{method: http.MethodGet, path: "/apps/tiers/instance_sizes", summary: "List App Platform instance sizes", response: appInstanceSizesResponse{}},
+	{method: http.MethodGet, path: "/databases/options", summary: "List managed database engine options", response: databaseOptionsResponse{}},
+	{method: http.MethodGet, path: "/catalog", summary: "List every resource, with per-section errors", response: catalogResponse{}},
+	{method: http.MethodGet, path: "/databases/sizes", summary: "List managed database node sizes", response: databaseSizesResponse{}},
+	{method: http.MethodGet, path: "/webhooks/deliveries", summary: "List recent webhook delivery attempts", response: webhookDeliveriesResponse{}},
+	{method: http.MethodGet, path: "/upstream/stats", summary: "Show DigitalOcean API retry counts and rate limit", response: upstreamStatsResponse{}},
+	{
+		method:  http.MethodGet,
+		path:    "/prices/{slug}/history",
+		summary: "Show the price history of a Droplet or App Platform instance size",
+		params: []apiParameter{
+			{name: "slug", in: "path", required: true, schema: map[string]interface{}{"type": "string"}},
+		},
+		response: priceHistoryResponse{},
+	},
+	{method: http.MethodGet, path: "/prices/changes", summary: "List recent price changes", response: priceChangesResponse{}},
+	{method: http.MethodGet, path: "/deprecations", summary: "List deprecated sizes, images and Kubernetes versions", response: deprecationsResponse{}},
+	{method: http.MethodPost, path: "/validate", summary: "Validate slugs against the catalog", request: validateRequest{}, response: validateResponse{}},
+	{method: http.MethodPost, path: "/estimate", summary: "Estimate the price of a bill of materials", request: estimateRequest{}, response: estimateResponse{}},
+	{
+		method:  http.MethodGet,
+		path:    "/recommend/sizes",
+		summary: "Recommend Droplet sizes meeting the given requirements",
+		params: []apiParameter{
+			{name: "vcpus", in: "query", description: "Minimum number of vCPUs.", schema: map[string]interface{}{"type": "integer"}},
+			{name: "memory", in: "query", description: "Minimum memory in GB.", schema: map[string]interface{}{"type": "number"}},
+			{name: "gpu", in: "query", description: "Whether the size has GPUs.", schema: map[string]interface{}{"type": "boolean"}},
+			regionParameter,
+			{name: "budget", in: "query", description: "Maximum monthly price.", schema: map[string]interface{}{"type": "number"}},
+			{name: "rank", in: "query", description: "Ranking of the results.", schema: map[string]interface{}{
+				"type":    "string",
+				"enum":    []string{rankByPrice, rankByPricePerVcpu, rankByPricePerGB},
+				"default": rankByPrice,
+			}},
+			limitParameter,
+		},
+		response: sizeRecommendationsResponse{},
+	},
+	{method: http.MethodGet, path: "/export/terraform", summary: "Export Terraform variables accepting the available slugs", params: []apiParameter{regionParameter}},
+	{method: http.MethodGet, path: "/export/pulumi", summary: "Export TypeScript enums of the available slugs", params: []apiParameter{regionParameter}},
+	{method: http.MethodPost, path: "/graphql", summary: "Query the catalog with GraphQL", request: graphQLRequest{}, response: graphql.Result{}, mediaType: "application/json"},
+	{method: http.MethodGet, path: "/events", summary: "Stream catalog changes as Server-Sent Events", mediaType: "text/event-stream"},
+	{method: http.MethodGet, path: "/openapi.json", summary: "Describe the API as an OpenAPI document", response: map[string]interface{}{}, mediaType: "application/json"},
+	{method: http.MethodGet, path: "/metrics", summary: "Export Prometheus metrics", mediaType: "text/plain"},
+}
+
+// openAPI serves an OpenAPI description of the API. Response schemas are
+// generated from the Go types, and the slug schemas list the slugs in the
+// current catalog as enums.
+func (h *handler) openAPI(w http.ResponseWriter, r *http.Request) {
+	schemas := make(map[string]interface{})
+	paths := make(map[string]interface{})
+	for _, op := range apiOperations {
+		item, ok := paths[op.path].(map[string]interface{})
+		if !ok {
+			item = make(map[string]interface{})
+			paths[op.path] = item
+		}
+		item[strings.ToLower(op.method)] = operationObject(op, schemas)
+	}
+
+	// The document is still useful without the enums, so a failed refresh
+	// only drops them.
+	var allowed allowedSlugs
+	cat, err := h.catalogFor(r.Context(), exportResources...)
+	if err != nil {
+		loggerFrom(r.Context()).Warn("omitting slug enums", "error", err)
+	} else {
+		allowed = allowedSlugsFor(cat, "")
+	}
+	for _, v := range exportVariables {
+		schema := map[string]interface{}{
+			"type":        "string",
+			"description": v.description + ".",
+		}
+		if slugs := v.slugs(allowed); len(slugs) > 0 {
+			schema["enum"] = slugs
+		}
+		schemas[v.enum] = schema
+	}
+
+	doc := map[string]interface{}{
+		"openapi": openAPIVersion,
+		"info": map[string]interface{}{
+			"title":       "DigitalOcean API Slugs",
+			"description": "The slugs and options accepted by the DigitalOcean API.",
+			"version":     apiVersion,
+		},
+		"paths": paths,
+		"components": map[string]interface{}{
+			"schemas": schemas,
+		},
+	}
+
+	writeJSONResponse(w, doc)
+}
+
+func operationObject(op apiOperation, schemas map[string]interface{}) map[string]interface{} {
+	params := make([]interface{}, 0, len(op.params)+1)
+	for _, p := range op.params {
+		param := map[string]interface{}{
+			"name":     p.name,
+			"in":       p.in,
+			"required": p.required,
+			"schema":   p.schema,
+		}
+		if p.description != "" {
+			param["description"] = p.description
+		}
+		params = append(params, param)
+	}
+
+	mediaType := op.mediaType
+	if mediaType == "" {
+		mediaType = "text/plain"
+	}
+	content := map[string]interface{}{
+		mediaType: map[string]interface{}{"schema": map[string]interface{}{"type": "string"}},
+	}
+	if op.response != nil && op.mediaType != "" {
+		content = map[string]interface{}{
+			mediaType: map[string]interface{}{"schema": schemaFor(reflect.TypeOf(op.response), schemas)},
+		}
+	} else if op.response != nil {
+		t := reflect.TypeOf(op.response)
+		schema := map[string]interface{}{"schema": schemaFor(t, schemas)}
+		content = map[string]interface{}{
+			"application/json": schema,
+			"application/yaml": schema,
+			"application/toml": schema,
+		}
+		if _, ok := op.response.(tabular); ok {
+			for _, mediaType := range []string{"text/csv", "text/markdown", "text/plain"} {
+				content[mediaType] = map[string]interface{}{"schema": map[string]interface{}{"type": "string"}}
+			}
+		}
+		params = append(params, formatParameter())
+	}
+
+	o := map[string]interface{}{
+		"summary":    op.summary,
+		"parameters": params,
+		"responses": map[string]interface{}{
+			"200": map[string]interface{}{
+				"description": "OK",
+				"content":     content,
+			},
+			"default": map[string]interface{}{
+				"description": "Error",
+				"content": map[string]interface{}{
+					"application/json": map[string]interface{}{"schema": schemaFor(reflect.TypeOf(errorResponse{}), schemas)},
+				},
+			},
+		},
+	}
+	if op.request != nil {
+		o["requestBody"] = map[string]interface{}{
+			"required": true,
+			"content": map[string]interface{}{
+				"application/json": map[string]interface{}{"schema": schemaFor(reflect.TypeOf(op.request), schemas)},
+			},
+		}
+	}
+
+	return o
+}
+
+func formatParameter() map[string]interface{} {
+	seen := make(map[string]bool)
+	var formats []string
+	for _, f := range mediaTypes {
+		if !seen[f] {
+			seen[f] = true
+			formats = append(formats, f)
+		}
+	}
+	sort.Strings(formats)
+
+	return map[string]interface{}{
+		"name":        "format",
+		"in":          "query",
+		"description": "Response format. Takes precedence over the Accept header.",
+		"schema":      map[string]interface{}{"type": "string", "enum": formats},
+	}
+}
+
+func schemaRef(name string) map[string]interface{} {
+	return map[string]interface{}{"$ref": "#/components/schemas/" + name}
+}
+
+var (
+	timeType       = reflect.TypeOf(time.Time{})
+	rawMessageType = reflect.TypeOf(json.RawMessage{})
+)
+
+// schemaFor returns the JSON Schema of t as encoded by encoding/json. Named
+// structs are added to schemas and referenced, which also terminates
+// recursive types.
+func schemaFor(t reflect.Type, schemas map[string]interface{}) map[string]interface{} {
+	switch t {
+	case timeType:
+		return map[string]interface{}{"type": "string", "format": "date-time"}
+	case rawMessageType:
+		return map[string]interface{}{}
+	}
+
+	switch t.Kind() {
+	case reflect.Ptr:
+		schema := schemaFor(t.Elem(), schemas)
+		if _, ok := schema["$ref"]; ok {
+			return map[string]interface{}{"allOf": []interface{}{schema}, "nullable": true}
+		}
+		schema["nullable"] = true
+		return schema
+	case reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]interface{}{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]interface{}{"type": "number"}
+	case reflect.String:
+		return map[string]interface{}{"type": "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return map[string]interface{}{"type": "string", "format": "byte"}
+		}
+		return map[string]interface{}{"type": "array", "items": schemaFor(t.Elem(), schemas)}
+	case reflect.Map:
+		return map[string]interface{}{"type": "object", "additionalProperties": schemaFor(t.Elem(), schemas)}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return structSchema(t, schemas)
+		}
+		name := schemaName(t)
+		if _, ok := schemas[name]; !ok {
+			schemas[name] = nil
+			schemas[name] = structSchema(t, schemas)
+		}
+		return schemaRef(name)
+	}
+
+	return map[string]interface{}{}
+}
+
+func structSchema(t reflect.Type, schemas map[string]interface{}) map[string]interface{} {
+	properties := make(map[string]interface{})
+	var required []string
+	addStructFields(t, schemas, properties, &required)
+
+	schema := map[string]interface{}{
+		"type":       "object",
+		"properties": properties,
+	}
+	if len(required) > 0 {
+		sort.Strings(required)
+		schema["required"] = required
+	}
+
+	return schema
+}
+
+// addStructFields adds the JSON fields of t to properties. Fields of embedded
+// structs without a JSON name are promoted, as encoding/json does.
+func addStructFields(t reflect.Type, schemas map[string]interface{}, properties map[string]interface{}, required *[]string) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+		if f.Anonymous && name == "" {
+			ft := f.Type
+			if ft.Kind() == reflect.Ptr {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				addStructFields(ft, schemas, properties, required)
+				continue
+			}
+		}
+		if !f.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+
+		properties[name] = schemaFor(f.Type, schemas)
+		if !strings.Contains(opts, "omitempty") {
+			*required = append(*required, name)
+		}
+	}
+}
+
+// schemaName returns the component name of a named type, e.g. Size for
+// godo.Size and SizesResponse for sizesResponse.
+func schemaName(t reflect.Type) string {
+	name := []rune(t.Name())
+	name[0] = unicode.ToUpper(name[0])
+
+	return string(name)
+}
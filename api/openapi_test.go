@@ -0,0 +1,73 @@
+package main
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"sort"
+	"strconv"
+	"testing"
+)
+
+// TestAPIOperationsMatchRoutes checks that every route registered in main is
+// described in the OpenAPI document, and that nothing else is.
+func TestAPIOperationsMatchRoutes(t *testing.T) {
+	f, err := parser.ParseFile(token.NewFileSet(), "main.go", nil, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	routes := make(map[string]bool)
+	ast.Inspect(f, func(n ast.Node) bool {
+		call, ok := n.(*ast.CallExpr)
+		if !ok || len(call.Args) == 0 {
+			return true
+		}
+		sel, ok := call.Fun.(*ast.SelectorExpr)
+		if !ok || (sel.Sel.Name != "Handle" && sel.Sel.Name != "HandleFunc") {
+			return true
+		}
+		if x, ok := sel.X.(*ast.Ident); !ok || x.Name != "mux" {
+			return true
+		}
+		lit, ok := call.Args[0].(*ast.BasicLit)
+		if !ok {
+			t.Errorf("route registered with a non-literal pattern at offset %d", call.Pos())
+			return true
+		}
+		pattern, err := strconv.Unquote(lit.Value)
+		if err != nil {
+			t.Fatal(err)
+		}
+		// The catch-all only serves 404s.
+		if pattern != "/" {
+			routes[pattern] = true
+		}
+		return true
+	})
+
+	documented := make(map[string]bool)
+	for _, op := range apiOperations {
+		documented[op.path] = true
+	}
+
+	var missing, extra []string
+	for route := range routes {
+		if !documented[route] {
+			missing = append(missing, route)
+		}
+	}
+	for path := range documented {
+		if !routes[path] {
+			extra = append(extra, path)
+		}
+	}
+	sort.Strings(missing)
+	sort.Strings(extra)
+	if len(missing) > 0 {
+		t.Errorf("routes missing from apiOperations: %v", missing)
+	}
+	if len(extra) > 0 {
+		t.Errorf("apiOperations not registered in main: %v", extra)
+	}
+}
@@ -0,0 +1,252 @@
+package main
+
+import (
+	"log/slog"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/andrewsomething/do-api-slugs/api/internal/catalog"
+)
+
+const (
+	priceHistoryLen = 100
+	priceChangesLen = 100
+)
+
+// pricePoint is the price of a slug as observed by a refresh. Only refreshes
+// where the price differs from the previous point are recorded.
+type pricePoint struct {
+	Resource     string    `json:"resource"`
+	PriceMonthly float64   `json:"price_monthly"`
+	PriceHourly  float64   `json:"price_hourly"`
+	RecordedAt   time.Time `json:"recorded_at"`
+}
+
+// priceChange describes a slug whose price moved between two refreshes.
+type priceChange struct {
+	Resource        string    `json:"resource"`
+	Slug            string    `json:"slug"`
+	OldPriceMonthly float64   `json:"old_price_monthly"`
+	NewPriceMonthly float64   `json:"new_price_monthly"`
+	OldPriceHourly  float64   `json:"old_price_hourly"`
+	NewPriceHourly  float64   `json:"new_price_hourly"`
+	ChangedAt       time.Time `json:"changed_at"`
+}
+
+type priceHistoryResponse struct {
+	Slug        string       `json:"slug"`
+	History     []pricePoint `json:"history"`
+	RetrievedAt string       `json:"retrieved_at"`
+}
+
+type priceChangesResponse struct {
+	Changes     []priceChange `json:"changes"`
+	RetrievedAt string        `json:"retrieved_at"`
+}
+
+// priceTracker records the price history of Droplet sizes and App Platform
+// instance sizes across refreshes.
+type priceTracker struct {
+	mu sync.RWMutex
+	// history is keyed by "<resource>:<slug>".
+	history map[string][]pricePoint
+	changes []priceChange
+}
+
+func newPriceTracker() *priceTracker {
+	return &priceTracker{
+		history: make(map[string][]pricePoint),
+	}
+}
+
+// slugPrice is the price of one slug in a catalog.
+type slugPrice struct {
+	slug  string
+	point pricePoint
+}
+
+// record adds the prices in event.Current to the history. A slug with no
+// history yet is first seeded with its price in event.Previous, so a change
+// made while the tracker wasn't running, such as across a restart from a
+// snapshot, is still reported.
+func (p *priceTracker) record(event refreshEvent) {
+	current := pricePoints(event.Current)
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	var previous map[string]pricePoint
+	for _, sp := range current {
+		key := sp.point.Resource + ":" + sp.slug
+		if _, ok := p.history[key]; !ok && event.Previous != nil {
+			if previous == nil {
+				previous = make(map[string]pricePoint)
+				for _, old := range pricePoints(event.Previous) {
+					previous[old.point.Resource+":"+old.slug] = old.point
+				}
+			}
+			if old, ok := previous[key]; ok {
+				p.observe(sp.slug, old)
+			}
+		}
+		p.observe(sp.slug, sp.point)
+	}
+}
+
+// pricePoints returns the price of every Droplet size and App Platform
+// instance size in c, in catalog order.
+func pricePoints(c *catalog.Catalog) []slugPrice {
+	var points []slugPrice
+	for _, s := range c.Sizes {
+		points = append(points, slugPrice{s.Slug, pricePoint{
+			Resource:     "size",
+			PriceMonthly: s.PriceMonthly,
+			PriceHourly:  s.PriceHourly,
+			RecordedAt:   c.RetrievedAt,
+		}})
+	}
+	for _, s := range c.AppInstanceSizes {
+		monthly, hourly, err := appInstanceSizePrices(s.USDPerMonth, s.USDPerSecond)
+		if err != nil {
+			slog.Warn("unable to parse price", "slug", s.Slug, "error", err)
+			continue
+		}
+		points = append(points, slugPrice{s.Slug, pricePoint{
+			Resource:     "app_instance_size",
+			PriceMonthly: monthly,
+			PriceHourly:  hourly,
+			RecordedAt:   c.RetrievedAt,
+		}})
+	}
+
+	return points
+}
+
+// priceState is the tracker's history as saved in a snapshot.
+type priceState struct {
+	History map[string][]pricePoint `json:"history"`
+	Changes []priceChange           `json:"changes"`
+}
+
+// state returns a copy of the history for saving.
+func (p *priceTracker) state() *priceState {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	history := make(map[string][]pricePoint, len(p.history))
+	for key, points := range p.history {
+		history[key] = append([]pricePoint(nil), points...)
+	}
+
+	return &priceState{
+		History: history,
+		Changes: append([]priceChange(nil), p.changes...),
+	}
+}
+
+// restore replaces the history with one loaded from a snapshot. It must be
+// called before the first refresh is recorded.
+func (p *priceTracker) restore(state *priceState) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if state.History != nil {
+		p.history = state.History
+	}
+	p.changes = state.Changes
+}
+
+// observe appends point to the slug's history if its price differs from the
+// last recorded one. p.mu must be held.
+func (p *priceTracker) observe(slug string, point pricePoint) {
+	key := point.Resource + ":" + slug
+	history := p.history[key]
+	if len(history) > 0 {
+		last := history[len(history)-1]
+		if last.PriceMonthly == point.PriceMonthly && last.PriceHourly == point.PriceHourly {
+			return
+		}
+		p.changes = append(p.changes, priceChange{
+			Resource:        point.Resource,
+			Slug:            slug,
+			OldPriceMonthly: last.PriceMonthly,
+			NewPriceMonthly: point.PriceMonthly,
+			OldPriceHourly:  last.PriceHourly,
+			NewPriceHourly:  point.PriceHourly,
+			ChangedAt:       point.RecordedAt,
+		})
+		if len(p.changes) > priceChangesLen {
+			p.changes = p.changes[len(p.changes)-priceChangesLen:]
+		}
+	}
+
+	history = append(history, point)
+	if len(history) > priceHistoryLen {
+		history = history[len(history)-priceHistoryLen:]
+	}
+	p.history[key] = history
+}
+
+func (p *priceTracker) historyFor(slug string) ([]pricePoint, bool) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	var history []pricePoint
+	for _, resource := range []string{"size", "app_instance_size"} {
+		history = append(history, p.history[resource+":"+slug]...)
+	}
+
+	return history, len(history) > 0
+}
+
+// recentChanges returns the recorded price changes, newest first.
+func (p *priceTracker) recentChanges() []priceChange {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	list := make([]priceChange, 0, len(p.changes))
+	for i := len(p.changes) - 1; i >= 0; i-- {
+		list = append(list, p.changes[i])
+	}
+
+	return list
+}
+
+// appInstanceSizePrices converts the string encoded App Platform prices to
+// monthly and hourly figures comparable with Droplet sizes.
+func appInstanceSizePrices(perMonth, perSecond string) (float64, float64, error) {
+	monthly, err := strconv.ParseFloat(perMonth, 64)
+	if err != nil {
+		return 0, 0, err
+	}
+	secondly, err := strconv.ParseFloat(perSecond, 64)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return monthly, secondly * 3600, nil
+}
+
+func (h *handler) priceHistory(w http.ResponseWriter, r *http.Request) {
+	slug := r.PathValue("slug")
+	history, ok := h.prices.historyFor(slug)
+	if !ok {
+		writeJSONError(w, http.StatusNotFound)
+		return
+	}
+	timestamp := time.Now().Format("Mon Jan _2 15:04:05 2006 UTC")
+	resp := priceHistoryResponse{
+		Slug:        slug,
+		History:     history,
+		RetrievedAt: timestamp,
+	}
+
+	writeResponse(w, r, resp)
+}
+
+func (h *handler) priceChanges(w http.ResponseWriter, r *http.Request) {
+	timestamp := time.Now().Format("Mon Jan _2 15:04:05 2006 UTC")
+	resp := priceChangesResponse{
+		Changes:     h.prices.recentChanges(),
+		RetrievedAt: timestamp,
+	}
+
+	writeResponse(w, r, resp)
+}
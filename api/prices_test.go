@@ -0,0 +1,119 @@
+package main
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/andrewsomething/do-api-slugs/api/internal/catalog"
+	"github.com/digitalocean/godo"
+)
+
+func sizeCatalog(at time.Time, prices map[string]float64) *catalog.Catalog {
+	c := &catalog.Catalog{RetrievedAt: at}
+	for slug, price := range prices {
+		c.Sizes = append(c.Sizes, godo.Size{Slug: slug, PriceMonthly: price, PriceHourly: price / 730})
+	}
+
+	return c
+}
+
+func TestPriceTrackerRecord(t *testing.T) {
+	t0 := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	t1 := t0.Add(time.Hour)
+	t2 := t1.Add(time.Hour)
+
+	tests := []struct {
+		name    string
+		events  []refreshEvent
+		changes int
+		history int
+	}{
+		{
+			name:    "first refresh sets a baseline",
+			events:  []refreshEvent{{Current: sizeCatalog(t0, map[string]float64{"s-1": 6})}},
+			changes: 0,
+			history: 1,
+		},
+		{
+			name: "unchanged price is not recorded again",
+			events: []refreshEvent{
+				{Current: sizeCatalog(t0, map[string]float64{"s-1": 6})},
+				{Previous: sizeCatalog(t0, map[string]float64{"s-1": 6}), Current: sizeCatalog(t1, map[string]float64{"s-1": 6})},
+			},
+			changes: 0,
+			history: 1,
+		},
+		{
+			name: "change between refreshes",
+			events: []refreshEvent{
+				{Current: sizeCatalog(t0, map[string]float64{"s-1": 6})},
+				{Previous: sizeCatalog(t0, map[string]float64{"s-1": 6}), Current: sizeCatalog(t1, map[string]float64{"s-1": 7})},
+			},
+			changes: 1,
+			history: 2,
+		},
+		{
+			name: "change since a seeded previous catalog",
+			events: []refreshEvent{
+				{Previous: sizeCatalog(t0, map[string]float64{"s-1": 6}), Current: sizeCatalog(t1, map[string]float64{"s-1": 7})},
+			},
+			changes: 1,
+			history: 2,
+		},
+		{
+			name: "existing history isn't reseeded",
+			events: []refreshEvent{
+				{Current: sizeCatalog(t0, map[string]float64{"s-1": 6})},
+				{Previous: sizeCatalog(t1, map[string]float64{"s-1": 5}), Current: sizeCatalog(t2, map[string]float64{"s-1": 6})},
+			},
+			changes: 0,
+			history: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := newPriceTracker()
+			for _, e := range tt.events {
+				p.record(e)
+			}
+			if got := len(p.recentChanges()); got != tt.changes {
+				t.Errorf("got %d changes, want %d", got, tt.changes)
+			}
+			history, _ := p.historyFor("s-1")
+			if len(history) != tt.history {
+				t.Errorf("got %d history points, want %d", len(history), tt.history)
+			}
+		})
+	}
+}
+
+func TestPriceHistorySnapshot(t *testing.T) {
+	t0 := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	path := filepath.Join(t.TempDir(), "snapshot.json")
+
+	h := &handler{refresher: newRefresher(nil, time.Hour), prices: newPriceTracker(), removals: newRemovalTracker()}
+	h.prices.record(refreshEvent{Current: sizeCatalog(t0, map[string]float64{"s-1": 6})})
+	h.prices.record(refreshEvent{Current: sizeCatalog(t0.Add(time.Hour), map[string]float64{"s-1": 7})})
+	cat := sizeCatalog(t0.Add(time.Hour), map[string]float64{"s-1": 7})
+	if err := h.saveSnapshot(cat, path); err != nil {
+		t.Fatal(err)
+	}
+
+	if loaded, err := catalog.Load(path); err != nil || len(loaded.Sizes) != 1 {
+		t.Fatalf("catalog.Load = %v, %v; want the saved catalog", loaded, err)
+	}
+
+	restored := &handler{refresher: newRefresher(nil, time.Hour), prices: newPriceTracker(), removals: newRemovalTracker()}
+	restored.loadSnapshot(path)
+	if restored.refresher.latest() == nil {
+		t.Fatal("snapshot catalog not seeded")
+	}
+	if history, _ := restored.prices.historyFor("s-1"); len(history) != 2 {
+		t.Errorf("got %d history points, want 2", len(history))
+	}
+	if changes := restored.prices.recentChanges(); len(changes) != 1 || changes[0].NewPriceMonthly != 7 {
+		t.Errorf("got changes %+v, want one change to 7", changes)
+	}
+}
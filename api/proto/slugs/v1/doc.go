@@ -0,0 +1,5 @@
+// Package slugsv1 contains the protobuf messages and gRPC service generated
+// from slugs.proto.
+package slugsv1
+
+//go:generate protoc -I ../.. --go_out=../.. --go_opt=paths=source_relative --go-grpc_out=../.. --go-grpc_opt=paths=source_relative slugs/v1/slugs.proto
@@ -0,0 +1,1749 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        (unknown)
+// source: slugs/v1/slugs.proto
+
+package slugsv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	structpb "google.golang.org/protobuf/types/known/structpb"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type ListImagesRequest_ImageType int32
+
+const (
+	ListImagesRequest_IMAGE_TYPE_UNSPECIFIED ListImagesRequest_ImageType = 0
+	ListImagesRequest_IMAGE_TYPE_APPS        ListImagesRequest_ImageType = 1
+	ListImagesRequest_IMAGE_TYPE_DISTROS     ListImagesRequest_ImageType = 2
+)
+
+// Enum value maps for ListImagesRequest_ImageType.
+var (
+	ListImagesRequest_ImageType_name = map[int32]string{
+		0: "IMAGE_TYPE_UNSPECIFIED",
+		1: "IMAGE_TYPE_APPS",
+		2: "IMAGE_TYPE_DISTROS",
+	}
+	ListImagesRequest_ImageType_value = map[string]int32{
+		"IMAGE_TYPE_UNSPECIFIED": 0,
+		"IMAGE_TYPE_APPS":        1,
+		"IMAGE_TYPE_DISTROS":     2,
+	}
+)
+
+func (x ListImagesRequest_ImageType) Enum() *ListImagesRequest_ImageType {
+	p := new(ListImagesRequest_ImageType)
+	*p = x
+	return p
+}
+
+func (x ListImagesRequest_ImageType) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (ListImagesRequest_ImageType) Descriptor() protoreflect.EnumDescriptor {
+	return file_slugs_v1_slugs_proto_enumTypes[0].Descriptor()
+}
+
+func (ListImagesRequest_ImageType) Type() protoreflect.EnumType {
+	return &file_slugs_v1_slugs_proto_enumTypes[0]
+}
+
+func (x ListImagesRequest_ImageType) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use ListImagesRequest_ImageType.Descriptor instead.
+func (ListImagesRequest_ImageType) EnumDescriptor() ([]byte, []int) {
+	return file_slugs_v1_slugs_proto_rawDescGZIP(), []int{12, 0}
+}
+
+// Size is a Droplet size. Memory is in MB, disk in GB and transfer in TB.
+type Size struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Slug          string                 `protobuf:"bytes,1,opt,name=slug,proto3" json:"slug,omitempty"`
+	Description   string                 `protobuf:"bytes,2,opt,name=description,proto3" json:"description,omitempty"`
+	Memory        int32                  `protobuf:"varint,3,opt,name=memory,proto3" json:"memory,omitempty"`
+	Vcpus         int32                  `protobuf:"varint,4,opt,name=vcpus,proto3" json:"vcpus,omitempty"`
+	Disk          int32                  `protobuf:"varint,5,opt,name=disk,proto3" json:"disk,omitempty"`
+	Transfer      float64                `protobuf:"fixed64,6,opt,name=transfer,proto3" json:"transfer,omitempty"`
+	PriceMonthly  float64                `protobuf:"fixed64,7,opt,name=price_monthly,json=priceMonthly,proto3" json:"price_monthly,omitempty"`
+	PriceHourly   float64                `protobuf:"fixed64,8,opt,name=price_hourly,json=priceHourly,proto3" json:"price_hourly,omitempty"`
+	Regions       []string               `protobuf:"bytes,9,rep,name=regions,proto3" json:"regions,omitempty"`
+	Available     bool                   `protobuf:"varint,10,opt,name=available,proto3" json:"available,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Size) Reset() {
+	*x = Size{}
+	mi := &file_slugs_v1_slugs_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Size) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Size) ProtoMessage() {}
+
+func (x *Size) ProtoReflect() protoreflect.Message {
+	mi := &file_slugs_v1_slugs_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Size.ProtoReflect.Descriptor instead.
+func (*Size) Descriptor() ([]byte, []int) {
+	return file_slugs_v1_slugs_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Size) GetSlug() string {
+	if x != nil {
+		return x.Slug
+	}
+	return ""
+}
+
+func (x *Size) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *Size) GetMemory() int32 {
+	if x != nil {
+		return x.Memory
+	}
+	return 0
+}
+
+func (x *Size) GetVcpus() int32 {
+	if x != nil {
+		return x.Vcpus
+	}
+	return 0
+}
+
+func (x *Size) GetDisk() int32 {
+	if x != nil {
+		return x.Disk
+	}
+	return 0
+}
+
+func (x *Size) GetTransfer() float64 {
+	if x != nil {
+		return x.Transfer
+	}
+	return 0
+}
+
+func (x *Size) GetPriceMonthly() float64 {
+	if x != nil {
+		return x.PriceMonthly
+	}
+	return 0
+}
+
+func (x *Size) GetPriceHourly() float64 {
+	if x != nil {
+		return x.PriceHourly
+	}
+	return 0
+}
+
+func (x *Size) GetRegions() []string {
+	if x != nil {
+		return x.Regions
+	}
+	return nil
+}
+
+func (x *Size) GetAvailable() bool {
+	if x != nil {
+		return x.Available
+	}
+	return false
+}
+
+type Region struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Slug          string                 `protobuf:"bytes,1,opt,name=slug,proto3" json:"slug,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Sizes         []string               `protobuf:"bytes,3,rep,name=sizes,proto3" json:"sizes,omitempty"`
+	Available     bool                   `protobuf:"varint,4,opt,name=available,proto3" json:"available,omitempty"`
+	Features      []string               `protobuf:"bytes,5,rep,name=features,proto3" json:"features,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Region) Reset() {
+	*x = Region{}
+	mi := &file_slugs_v1_slugs_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Region) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Region) ProtoMessage() {}
+
+func (x *Region) ProtoReflect() protoreflect.Message {
+	mi := &file_slugs_v1_slugs_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Region.ProtoReflect.Descriptor instead.
+func (*Region) Descriptor() ([]byte, []int) {
+	return file_slugs_v1_slugs_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Region) GetSlug() string {
+	if x != nil {
+		return x.Slug
+	}
+	return ""
+}
+
+func (x *Region) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Region) GetSizes() []string {
+	if x != nil {
+		return x.Sizes
+	}
+	return nil
+}
+
+func (x *Region) GetAvailable() bool {
+	if x != nil {
+		return x.Available
+	}
+	return false
+}
+
+func (x *Region) GetFeatures() []string {
+	if x != nil {
+		return x.Features
+	}
+	return nil
+}
+
+type Image struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Slug          string                 `protobuf:"bytes,2,opt,name=slug,proto3" json:"slug,omitempty"`
+	Name          string                 `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"`
+	Type          string                 `protobuf:"bytes,4,opt,name=type,proto3" json:"type,omitempty"`
+	Distribution  string                 `protobuf:"bytes,5,opt,name=distribution,proto3" json:"distribution,omitempty"`
+	Public        bool                   `protobuf:"varint,6,opt,name=public,proto3" json:"public,omitempty"`
+	Regions       []string               `protobuf:"bytes,7,rep,name=regions,proto3" json:"regions,omitempty"`
+	MinDiskSize   int32                  `protobuf:"varint,8,opt,name=min_disk_size,json=minDiskSize,proto3" json:"min_disk_size,omitempty"`
+	SizeGigabytes float64                `protobuf:"fixed64,9,opt,name=size_gigabytes,json=sizeGigabytes,proto3" json:"size_gigabytes,omitempty"`
+	CreatedAt     string                 `protobuf:"bytes,10,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	Description   string                 `protobuf:"bytes,11,opt,name=description,proto3" json:"description,omitempty"`
+	Status        string                 `protobuf:"bytes,12,opt,name=status,proto3" json:"status,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Image) Reset() {
+	*x = Image{}
+	mi := &file_slugs_v1_slugs_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Image) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Image) ProtoMessage() {}
+
+func (x *Image) ProtoReflect() protoreflect.Message {
+	mi := &file_slugs_v1_slugs_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Image.ProtoReflect.Descriptor instead.
+func (*Image) Descriptor() ([]byte, []int) {
+	return file_slugs_v1_slugs_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *Image) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *Image) GetSlug() string {
+	if x != nil {
+		return x.Slug
+	}
+	return ""
+}
+
+func (x *Image) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Image) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *Image) GetDistribution() string {
+	if x != nil {
+		return x.Distribution
+	}
+	return ""
+}
+
+func (x *Image) GetPublic() bool {
+	if x != nil {
+		return x.Public
+	}
+	return false
+}
+
+func (x *Image) GetRegions() []string {
+	if x != nil {
+		return x.Regions
+	}
+	return nil
+}
+
+func (x *Image) GetMinDiskSize() int32 {
+	if x != nil {
+		return x.MinDiskSize
+	}
+	return 0
+}
+
+func (x *Image) GetSizeGigabytes() float64 {
+	if x != nil {
+		return x.SizeGigabytes
+	}
+	return 0
+}
+
+func (x *Image) GetCreatedAt() string {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return ""
+}
+
+func (x *Image) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *Image) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+type KubernetesVersion struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	Slug              string                 `protobuf:"bytes,1,opt,name=slug,proto3" json:"slug,omitempty"`
+	KubernetesVersion string                 `protobuf:"bytes,2,opt,name=kubernetes_version,json=kubernetesVersion,proto3" json:"kubernetes_version,omitempty"`
+	SupportedFeatures []string               `protobuf:"bytes,3,rep,name=supported_features,json=supportedFeatures,proto3" json:"supported_features,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *KubernetesVersion) Reset() {
+	*x = KubernetesVersion{}
+	mi := &file_slugs_v1_slugs_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *KubernetesVersion) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*KubernetesVersion) ProtoMessage() {}
+
+func (x *KubernetesVersion) ProtoReflect() protoreflect.Message {
+	mi := &file_slugs_v1_slugs_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use KubernetesVersion.ProtoReflect.Descriptor instead.
+func (*KubernetesVersion) Descriptor() ([]byte, []int) {
+	return file_slugs_v1_slugs_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *KubernetesVersion) GetSlug() string {
+	if x != nil {
+		return x.Slug
+	}
+	return ""
+}
+
+func (x *KubernetesVersion) GetKubernetesVersion() string {
+	if x != nil {
+		return x.KubernetesVersion
+	}
+	return ""
+}
+
+func (x *KubernetesVersion) GetSupportedFeatures() []string {
+	if x != nil {
+		return x.SupportedFeatures
+	}
+	return nil
+}
+
+type KubernetesRegion struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Slug          string                 `protobuf:"bytes,1,opt,name=slug,proto3" json:"slug,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *KubernetesRegion) Reset() {
+	*x = KubernetesRegion{}
+	mi := &file_slugs_v1_slugs_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *KubernetesRegion) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*KubernetesRegion) ProtoMessage() {}
+
+func (x *KubernetesRegion) ProtoReflect() protoreflect.Message {
+	mi := &file_slugs_v1_slugs_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use KubernetesRegion.ProtoReflect.Descriptor instead.
+func (*KubernetesRegion) Descriptor() ([]byte, []int) {
+	return file_slugs_v1_slugs_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *KubernetesRegion) GetSlug() string {
+	if x != nil {
+		return x.Slug
+	}
+	return ""
+}
+
+func (x *KubernetesRegion) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+type KubernetesNodeSize struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Slug          string                 `protobuf:"bytes,1,opt,name=slug,proto3" json:"slug,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *KubernetesNodeSize) Reset() {
+	*x = KubernetesNodeSize{}
+	mi := &file_slugs_v1_slugs_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *KubernetesNodeSize) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*KubernetesNodeSize) ProtoMessage() {}
+
+func (x *KubernetesNodeSize) ProtoReflect() protoreflect.Message {
+	mi := &file_slugs_v1_slugs_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use KubernetesNodeSize.ProtoReflect.Descriptor instead.
+func (*KubernetesNodeSize) Descriptor() ([]byte, []int) {
+	return file_slugs_v1_slugs_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *KubernetesNodeSize) GetSlug() string {
+	if x != nil {
+		return x.Slug
+	}
+	return ""
+}
+
+func (x *KubernetesNodeSize) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+type KubernetesOptions struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Versions      []*KubernetesVersion   `protobuf:"bytes,1,rep,name=versions,proto3" json:"versions,omitempty"`
+	Regions       []*KubernetesRegion    `protobuf:"bytes,2,rep,name=regions,proto3" json:"regions,omitempty"`
+	Sizes         []*KubernetesNodeSize  `protobuf:"bytes,3,rep,name=sizes,proto3" json:"sizes,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *KubernetesOptions) Reset() {
+	*x = KubernetesOptions{}
+	mi := &file_slugs_v1_slugs_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *KubernetesOptions) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*KubernetesOptions) ProtoMessage() {}
+
+func (x *KubernetesOptions) ProtoReflect() protoreflect.Message {
+	mi := &file_slugs_v1_slugs_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use KubernetesOptions.ProtoReflect.Descriptor instead.
+func (*KubernetesOptions) Descriptor() ([]byte, []int) {
+	return file_slugs_v1_slugs_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *KubernetesOptions) GetVersions() []*KubernetesVersion {
+	if x != nil {
+		return x.Versions
+	}
+	return nil
+}
+
+func (x *KubernetesOptions) GetRegions() []*KubernetesRegion {
+	if x != nil {
+		return x.Regions
+	}
+	return nil
+}
+
+func (x *KubernetesOptions) GetSizes() []*KubernetesNodeSize {
+	if x != nil {
+		return x.Sizes
+	}
+	return nil
+}
+
+// AppInstanceSize is an App Platform instance size. Numeric values are
+// strings, as returned by the DigitalOcean API.
+type AppInstanceSize struct {
+	state                 protoimpl.MessageState `protogen:"open.v1"`
+	Slug                  string                 `protobuf:"bytes,1,opt,name=slug,proto3" json:"slug,omitempty"`
+	Name                  string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	CpuType               string                 `protobuf:"bytes,3,opt,name=cpu_type,json=cpuType,proto3" json:"cpu_type,omitempty"`
+	Cpus                  string                 `protobuf:"bytes,4,opt,name=cpus,proto3" json:"cpus,omitempty"`
+	MemoryBytes           string                 `protobuf:"bytes,5,opt,name=memory_bytes,json=memoryBytes,proto3" json:"memory_bytes,omitempty"`
+	UsdPerMonth           string                 `protobuf:"bytes,6,opt,name=usd_per_month,json=usdPerMonth,proto3" json:"usd_per_month,omitempty"`
+	UsdPerSecond          string                 `protobuf:"bytes,7,opt,name=usd_per_second,json=usdPerSecond,proto3" json:"usd_per_second,omitempty"`
+	TierSlug              string                 `protobuf:"bytes,8,opt,name=tier_slug,json=tierSlug,proto3" json:"tier_slug,omitempty"`
+	Scalable              bool                   `protobuf:"varint,9,opt,name=scalable,proto3" json:"scalable,omitempty"`
+	SingleInstanceOnly    bool                   `protobuf:"varint,10,opt,name=single_instance_only,json=singleInstanceOnly,proto3" json:"single_instance_only,omitempty"`
+	DeprecationIntent     bool                   `protobuf:"varint,11,opt,name=deprecation_intent,json=deprecationIntent,proto3" json:"deprecation_intent,omitempty"`
+	BandwidthAllowanceGib string                 `protobuf:"bytes,12,opt,name=bandwidth_allowance_gib,json=bandwidthAllowanceGib,proto3" json:"bandwidth_allowance_gib,omitempty"`
+	unknownFields         protoimpl.UnknownFields
+	sizeCache             protoimpl.SizeCache
+}
+
+func (x *AppInstanceSize) Reset() {
+	*x = AppInstanceSize{}
+	mi := &file_slugs_v1_slugs_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AppInstanceSize) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AppInstanceSize) ProtoMessage() {}
+
+func (x *AppInstanceSize) ProtoReflect() protoreflect.Message {
+	mi := &file_slugs_v1_slugs_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AppInstanceSize.ProtoReflect.Descriptor instead.
+func (*AppInstanceSize) Descriptor() ([]byte, []int) {
+	return file_slugs_v1_slugs_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *AppInstanceSize) GetSlug() string {
+	if x != nil {
+		return x.Slug
+	}
+	return ""
+}
+
+func (x *AppInstanceSize) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *AppInstanceSize) GetCpuType() string {
+	if x != nil {
+		return x.CpuType
+	}
+	return ""
+}
+
+func (x *AppInstanceSize) GetCpus() string {
+	if x != nil {
+		return x.Cpus
+	}
+	return ""
+}
+
+func (x *AppInstanceSize) GetMemoryBytes() string {
+	if x != nil {
+		return x.MemoryBytes
+	}
+	return ""
+}
+
+func (x *AppInstanceSize) GetUsdPerMonth() string {
+	if x != nil {
+		return x.UsdPerMonth
+	}
+	return ""
+}
+
+func (x *AppInstanceSize) GetUsdPerSecond() string {
+	if x != nil {
+		return x.UsdPerSecond
+	}
+	return ""
+}
+
+func (x *AppInstanceSize) GetTierSlug() string {
+	if x != nil {
+		return x.TierSlug
+	}
+	return ""
+}
+
+func (x *AppInstanceSize) GetScalable() bool {
+	if x != nil {
+		return x.Scalable
+	}
+	return false
+}
+
+func (x *AppInstanceSize) GetSingleInstanceOnly() bool {
+	if x != nil {
+		return x.SingleInstanceOnly
+	}
+	return false
+}
+
+func (x *AppInstanceSize) GetDeprecationIntent() bool {
+	if x != nil {
+		return x.DeprecationIntent
+	}
+	return false
+}
+
+func (x *AppInstanceSize) GetBandwidthAllowanceGib() string {
+	if x != nil {
+		return x.BandwidthAllowanceGib
+	}
+	return ""
+}
+
+type ListSizesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListSizesRequest) Reset() {
+	*x = ListSizesRequest{}
+	mi := &file_slugs_v1_slugs_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListSizesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListSizesRequest) ProtoMessage() {}
+
+func (x *ListSizesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_slugs_v1_slugs_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListSizesRequest.ProtoReflect.Descriptor instead.
+func (*ListSizesRequest) Descriptor() ([]byte, []int) {
+	return file_slugs_v1_slugs_proto_rawDescGZIP(), []int{8}
+}
+
+type ListSizesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Sizes         []*Size                `protobuf:"bytes,1,rep,name=sizes,proto3" json:"sizes,omitempty"`
+	RetrievedAt   *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=retrieved_at,json=retrievedAt,proto3" json:"retrieved_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListSizesResponse) Reset() {
+	*x = ListSizesResponse{}
+	mi := &file_slugs_v1_slugs_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListSizesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListSizesResponse) ProtoMessage() {}
+
+func (x *ListSizesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_slugs_v1_slugs_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListSizesResponse.ProtoReflect.Descriptor instead.
+func (*ListSizesResponse) Descriptor() ([]byte, []int) {
+	return file_slugs_v1_slugs_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *ListSizesResponse) GetSizes() []*Size {
+	if x != nil {
+		return x.Sizes
+	}
+	return nil
+}
+
+func (x *ListSizesResponse) GetRetrievedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.RetrievedAt
+	}
+	return nil
+}
+
+type ListRegionsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListRegionsRequest) Reset() {
+	*x = ListRegionsRequest{}
+	mi := &file_slugs_v1_slugs_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListRegionsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListRegionsRequest) ProtoMessage() {}
+
+func (x *ListRegionsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_slugs_v1_slugs_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListRegionsRequest.ProtoReflect.Descriptor instead.
+func (*ListRegionsRequest) Descriptor() ([]byte, []int) {
+	return file_slugs_v1_slugs_proto_rawDescGZIP(), []int{10}
+}
+
+type ListRegionsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Regions       []*Region              `protobuf:"bytes,1,rep,name=regions,proto3" json:"regions,omitempty"`
+	RetrievedAt   *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=retrieved_at,json=retrievedAt,proto3" json:"retrieved_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListRegionsResponse) Reset() {
+	*x = ListRegionsResponse{}
+	mi := &file_slugs_v1_slugs_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListRegionsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListRegionsResponse) ProtoMessage() {}
+
+func (x *ListRegionsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_slugs_v1_slugs_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListRegionsResponse.ProtoReflect.Descriptor instead.
+func (*ListRegionsResponse) Descriptor() ([]byte, []int) {
+	return file_slugs_v1_slugs_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *ListRegionsResponse) GetRegions() []*Region {
+	if x != nil {
+		return x.Regions
+	}
+	return nil
+}
+
+func (x *ListRegionsResponse) GetRetrievedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.RetrievedAt
+	}
+	return nil
+}
+
+type ListImagesRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Type selects 1-Click application or distribution images. Both are
+	// listed if unspecified.
+	Type          ListImagesRequest_ImageType `protobuf:"varint,1,opt,name=type,proto3,enum=slugs.v1.ListImagesRequest_ImageType" json:"type,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListImagesRequest) Reset() {
+	*x = ListImagesRequest{}
+	mi := &file_slugs_v1_slugs_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListImagesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListImagesRequest) ProtoMessage() {}
+
+func (x *ListImagesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_slugs_v1_slugs_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListImagesRequest.ProtoReflect.Descriptor instead.
+func (*ListImagesRequest) Descriptor() ([]byte, []int) {
+	return file_slugs_v1_slugs_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *ListImagesRequest) GetType() ListImagesRequest_ImageType {
+	if x != nil {
+		return x.Type
+	}
+	return ListImagesRequest_IMAGE_TYPE_UNSPECIFIED
+}
+
+type ListImagesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Images        []*Image               `protobuf:"bytes,1,rep,name=images,proto3" json:"images,omitempty"`
+	RetrievedAt   *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=retrieved_at,json=retrievedAt,proto3" json:"retrieved_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListImagesResponse) Reset() {
+	*x = ListImagesResponse{}
+	mi := &file_slugs_v1_slugs_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListImagesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListImagesResponse) ProtoMessage() {}
+
+func (x *ListImagesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_slugs_v1_slugs_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListImagesResponse.ProtoReflect.Descriptor instead.
+func (*ListImagesResponse) Descriptor() ([]byte, []int) {
+	return file_slugs_v1_slugs_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *ListImagesResponse) GetImages() []*Image {
+	if x != nil {
+		return x.Images
+	}
+	return nil
+}
+
+func (x *ListImagesResponse) GetRetrievedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.RetrievedAt
+	}
+	return nil
+}
+
+type GetKubernetesOptionsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetKubernetesOptionsRequest) Reset() {
+	*x = GetKubernetesOptionsRequest{}
+	mi := &file_slugs_v1_slugs_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetKubernetesOptionsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetKubernetesOptionsRequest) ProtoMessage() {}
+
+func (x *GetKubernetesOptionsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_slugs_v1_slugs_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetKubernetesOptionsRequest.ProtoReflect.Descriptor instead.
+func (*GetKubernetesOptionsRequest) Descriptor() ([]byte, []int) {
+	return file_slugs_v1_slugs_proto_rawDescGZIP(), []int{14}
+}
+
+type GetKubernetesOptionsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Options       *KubernetesOptions     `protobuf:"bytes,1,opt,name=options,proto3" json:"options,omitempty"`
+	RetrievedAt   *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=retrieved_at,json=retrievedAt,proto3" json:"retrieved_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetKubernetesOptionsResponse) Reset() {
+	*x = GetKubernetesOptionsResponse{}
+	mi := &file_slugs_v1_slugs_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetKubernetesOptionsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetKubernetesOptionsResponse) ProtoMessage() {}
+
+func (x *GetKubernetesOptionsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_slugs_v1_slugs_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetKubernetesOptionsResponse.ProtoReflect.Descriptor instead.
+func (*GetKubernetesOptionsResponse) Descriptor() ([]byte, []int) {
+	return file_slugs_v1_slugs_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *GetKubernetesOptionsResponse) GetOptions() *KubernetesOptions {
+	if x != nil {
+		return x.Options
+	}
+	return nil
+}
+
+func (x *GetKubernetesOptionsResponse) GetRetrievedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.RetrievedAt
+	}
+	return nil
+}
+
+type ListAppInstanceSizesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListAppInstanceSizesRequest) Reset() {
+	*x = ListAppInstanceSizesRequest{}
+	mi := &file_slugs_v1_slugs_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListAppInstanceSizesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListAppInstanceSizesRequest) ProtoMessage() {}
+
+func (x *ListAppInstanceSizesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_slugs_v1_slugs_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListAppInstanceSizesRequest.ProtoReflect.Descriptor instead.
+func (*ListAppInstanceSizesRequest) Descriptor() ([]byte, []int) {
+	return file_slugs_v1_slugs_proto_rawDescGZIP(), []int{16}
+}
+
+type ListAppInstanceSizesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Sizes         []*AppInstanceSize     `protobuf:"bytes,1,rep,name=sizes,proto3" json:"sizes,omitempty"`
+	RetrievedAt   *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=retrieved_at,json=retrievedAt,proto3" json:"retrieved_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListAppInstanceSizesResponse) Reset() {
+	*x = ListAppInstanceSizesResponse{}
+	mi := &file_slugs_v1_slugs_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListAppInstanceSizesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListAppInstanceSizesResponse) ProtoMessage() {}
+
+func (x *ListAppInstanceSizesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_slugs_v1_slugs_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListAppInstanceSizesResponse.ProtoReflect.Descriptor instead.
+func (*ListAppInstanceSizesResponse) Descriptor() ([]byte, []int) {
+	return file_slugs_v1_slugs_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *ListAppInstanceSizesResponse) GetSizes() []*AppInstanceSize {
+	if x != nil {
+		return x.Sizes
+	}
+	return nil
+}
+
+func (x *ListAppInstanceSizesResponse) GetRetrievedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.RetrievedAt
+	}
+	return nil
+}
+
+type GetDatabaseOptionsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetDatabaseOptionsRequest) Reset() {
+	*x = GetDatabaseOptionsRequest{}
+	mi := &file_slugs_v1_slugs_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetDatabaseOptionsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetDatabaseOptionsRequest) ProtoMessage() {}
+
+func (x *GetDatabaseOptionsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_slugs_v1_slugs_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetDatabaseOptionsRequest.ProtoReflect.Descriptor instead.
+func (*GetDatabaseOptionsRequest) Descriptor() ([]byte, []int) {
+	return file_slugs_v1_slugs_proto_rawDescGZIP(), []int{18}
+}
+
+// GetDatabaseOptionsResponse holds the options for each database engine,
+// keyed by engine, in the same shape as /databases/options.
+type GetDatabaseOptionsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Options       *structpb.Struct       `protobuf:"bytes,1,opt,name=options,proto3" json:"options,omitempty"`
+	RetrievedAt   *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=retrieved_at,json=retrievedAt,proto3" json:"retrieved_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetDatabaseOptionsResponse) Reset() {
+	*x = GetDatabaseOptionsResponse{}
+	mi := &file_slugs_v1_slugs_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetDatabaseOptionsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetDatabaseOptionsResponse) ProtoMessage() {}
+
+func (x *GetDatabaseOptionsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_slugs_v1_slugs_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetDatabaseOptionsResponse.ProtoReflect.Descriptor instead.
+func (*GetDatabaseOptionsResponse) Descriptor() ([]byte, []int) {
+	return file_slugs_v1_slugs_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *GetDatabaseOptionsResponse) GetOptions() *structpb.Struct {
+	if x != nil {
+		return x.Options
+	}
+	return nil
+}
+
+func (x *GetDatabaseOptionsResponse) GetRetrievedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.RetrievedAt
+	}
+	return nil
+}
+
+type WatchChangesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WatchChangesRequest) Reset() {
+	*x = WatchChangesRequest{}
+	mi := &file_slugs_v1_slugs_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchChangesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchChangesRequest) ProtoMessage() {}
+
+func (x *WatchChangesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_slugs_v1_slugs_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchChangesRequest.ProtoReflect.Descriptor instead.
+func (*WatchChangesRequest) Descriptor() ([]byte, []int) {
+	return file_slugs_v1_slugs_proto_rawDescGZIP(), []int{20}
+}
+
+// FieldChange holds the JSON values of a field before and after a change.
+type FieldChange struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Old           *structpb.Value        `protobuf:"bytes,1,opt,name=old,proto3" json:"old,omitempty"`
+	New           *structpb.Value        `protobuf:"bytes,2,opt,name=new,proto3" json:"new,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FieldChange) Reset() {
+	*x = FieldChange{}
+	mi := &file_slugs_v1_slugs_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FieldChange) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FieldChange) ProtoMessage() {}
+
+func (x *FieldChange) ProtoReflect() protoreflect.Message {
+	mi := &file_slugs_v1_slugs_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FieldChange.ProtoReflect.Descriptor instead.
+func (*FieldChange) Descriptor() ([]byte, []int) {
+	return file_slugs_v1_slugs_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *FieldChange) GetOld() *structpb.Value {
+	if x != nil {
+		return x.Old
+	}
+	return nil
+}
+
+func (x *FieldChange) GetNew() *structpb.Value {
+	if x != nil {
+		return x.New
+	}
+	return nil
+}
+
+// Change is a single resource that was added, removed or updated.
+type Change struct {
+	state         protoimpl.MessageState  `protogen:"open.v1"`
+	Resource      string                  `protobuf:"bytes,1,opt,name=resource,proto3" json:"resource,omitempty"`
+	Slug          string                  `protobuf:"bytes,2,opt,name=slug,proto3" json:"slug,omitempty"`
+	Action        string                  `protobuf:"bytes,3,opt,name=action,proto3" json:"action,omitempty"`
+	Fields        map[string]*FieldChange `protobuf:"bytes,4,rep,name=fields,proto3" json:"fields,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Change) Reset() {
+	*x = Change{}
+	mi := &file_slugs_v1_slugs_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Change) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Change) ProtoMessage() {}
+
+func (x *Change) ProtoReflect() protoreflect.Message {
+	mi := &file_slugs_v1_slugs_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Change.ProtoReflect.Descriptor instead.
+func (*Change) Descriptor() ([]byte, []int) {
+	return file_slugs_v1_slugs_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *Change) GetResource() string {
+	if x != nil {
+		return x.Resource
+	}
+	return ""
+}
+
+func (x *Change) GetSlug() string {
+	if x != nil {
+		return x.Slug
+	}
+	return ""
+}
+
+func (x *Change) GetAction() string {
+	if x != nil {
+		return x.Action
+	}
+	return ""
+}
+
+func (x *Change) GetFields() map[string]*FieldChange {
+	if x != nil {
+		return x.Fields
+	}
+	return nil
+}
+
+type CatalogChangeEvent struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	RetrievedAt   *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=retrieved_at,json=retrievedAt,proto3" json:"retrieved_at,omitempty"`
+	Changes       []*Change              `protobuf:"bytes,3,rep,name=changes,proto3" json:"changes,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CatalogChangeEvent) Reset() {
+	*x = CatalogChangeEvent{}
+	mi := &file_slugs_v1_slugs_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CatalogChangeEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CatalogChangeEvent) ProtoMessage() {}
+
+func (x *CatalogChangeEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_slugs_v1_slugs_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CatalogChangeEvent.ProtoReflect.Descriptor instead.
+func (*CatalogChangeEvent) Descriptor() ([]byte, []int) {
+	return file_slugs_v1_slugs_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *CatalogChangeEvent) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *CatalogChangeEvent) GetRetrievedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.RetrievedAt
+	}
+	return nil
+}
+
+func (x *CatalogChangeEvent) GetChanges() []*Change {
+	if x != nil {
+		return x.Changes
+	}
+	return nil
+}
+
+var File_slugs_v1_slugs_proto protoreflect.FileDescriptor
+
+const file_slugs_v1_slugs_proto_rawDesc = "" +
+	"\n" +
+	"\x14slugs/v1/slugs.proto\x12\bslugs.v1\x1a\x1cgoogle/protobuf/struct.proto\x1a\x1fgoogle/protobuf/timestamp.proto\"\x9a\x02\n" +
+	"\x04Size\x12\x12\n" +
+	"\x04slug\x18\x01 \x01(\tR\x04slug\x12 \n" +
+	"\vdescription\x18\x02 \x01(\tR\vdescription\x12\x16\n" +
+	"\x06memory\x18\x03 \x01(\x05R\x06memory\x12\x14\n" +
+	"\x05vcpus\x18\x04 \x01(\x05R\x05vcpus\x12\x12\n" +
+	"\x04disk\x18\x05 \x01(\x05R\x04disk\x12\x1a\n" +
+	"\btransfer\x18\x06 \x01(\x01R\btransfer\x12#\n" +
+	"\rprice_monthly\x18\a \x01(\x01R\fpriceMonthly\x12!\n" +
+	"\fprice_hourly\x18\b \x01(\x01R\vpriceHourly\x12\x18\n" +
+	"\aregions\x18\t \x03(\tR\aregions\x12\x1c\n" +
+	"\tavailable\x18\n" +
+	" \x01(\bR\tavailable\"\x80\x01\n" +
+	"\x06Region\x12\x12\n" +
+	"\x04slug\x18\x01 \x01(\tR\x04slug\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x14\n" +
+	"\x05sizes\x18\x03 \x03(\tR\x05sizes\x12\x1c\n" +
+	"\tavailable\x18\x04 \x01(\bR\tavailable\x12\x1a\n" +
+	"\bfeatures\x18\x05 \x03(\tR\bfeatures\"\xcd\x02\n" +
+	"\x05Image\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x12\n" +
+	"\x04slug\x18\x02 \x01(\tR\x04slug\x12\x12\n" +
+	"\x04name\x18\x03 \x01(\tR\x04name\x12\x12\n" +
+	"\x04type\x18\x04 \x01(\tR\x04type\x12\"\n" +
+	"\fdistribution\x18\x05 \x01(\tR\fdistribution\x12\x16\n" +
+	"\x06public\x18\x06 \x01(\bR\x06public\x12\x18\n" +
+	"\aregions\x18\a \x03(\tR\aregions\x12\"\n" +
+	"\rmin_disk_size\x18\b \x01(\x05R\vminDiskSize\x12%\n" +
+	"\x0esize_gigabytes\x18\t \x01(\x01R\rsizeGigabytes\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\n" +
+	" \x01(\tR\tcreatedAt\x12 \n" +
+	"\vdescription\x18\v \x01(\tR\vdescription\x12\x16\n" +
+	"\x06status\x18\f \x01(\tR\x06status\"\x85\x01\n" +
+	"\x11KubernetesVersion\x12\x12\n" +
+	"\x04slug\x18\x01 \x01(\tR\x04slug\x12-\n" +
+	"\x12kubernetes_version\x18\x02 \x01(\tR\x11kubernetesVersion\x12-\n" +
+	"\x12supported_features\x18\x03 \x03(\tR\x11supportedFeatures\":\n" +
+	"\x10KubernetesRegion\x12\x12\n" +
+	"\x04slug\x18\x01 \x01(\tR\x04slug\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\"<\n" +
+	"\x12KubernetesNodeSize\x12\x12\n" +
+	"\x04slug\x18\x01 \x01(\tR\x04slug\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\"\xb6\x01\n" +
+	"\x11KubernetesOptions\x127\n" +
+	"\bversions\x18\x01 \x03(\v2\x1b.slugs.v1.KubernetesVersionR\bversions\x124\n" +
+	"\aregions\x18\x02 \x03(\v2\x1a.slugs.v1.KubernetesRegionR\aregions\x122\n" +
+	"\x05sizes\x18\x03 \x03(\v2\x1c.slugs.v1.KubernetesNodeSizeR\x05sizes\"\xa7\x03\n" +
+	"\x0fAppInstanceSize\x12\x12\n" +
+	"\x04slug\x18\x01 \x01(\tR\x04slug\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x19\n" +
+	"\bcpu_type\x18\x03 \x01(\tR\acpuType\x12\x12\n" +
+	"\x04cpus\x18\x04 \x01(\tR\x04cpus\x12!\n" +
+	"\fmemory_bytes\x18\x05 \x01(\tR\vmemoryBytes\x12\"\n" +
+	"\rusd_per_month\x18\x06 \x01(\tR\vusdPerMonth\x12$\n" +
+	"\x0eusd_per_second\x18\a \x01(\tR\fusdPerSecond\x12\x1b\n" +
+	"\ttier_slug\x18\b \x01(\tR\btierSlug\x12\x1a\n" +
+	"\bscalable\x18\t \x01(\bR\bscalable\x120\n" +
+	"\x14single_instance_only\x18\n" +
+	" \x01(\bR\x12singleInstanceOnly\x12-\n" +
+	"\x12deprecation_intent\x18\v \x01(\bR\x11deprecationIntent\x126\n" +
+	"\x17bandwidth_allowance_gib\x18\f \x01(\tR\x15bandwidthAllowanceGib\"\x12\n" +
+	"\x10ListSizesRequest\"x\n" +
+	"\x11ListSizesResponse\x12$\n" +
+	"\x05sizes\x18\x01 \x03(\v2\x0e.slugs.v1.SizeR\x05sizes\x12=\n" +
+	"\fretrieved_at\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\vretrievedAt\"\x14\n" +
+	"\x12ListRegionsRequest\"\x80\x01\n" +
+	"\x13ListRegionsResponse\x12*\n" +
+	"\aregions\x18\x01 \x03(\v2\x10.slugs.v1.RegionR\aregions\x12=\n" +
+	"\fretrieved_at\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\vretrievedAt\"\xa4\x01\n" +
+	"\x11ListImagesRequest\x129\n" +
+	"\x04type\x18\x01 \x01(\x0e2%.slugs.v1.ListImagesRequest.ImageTypeR\x04type\"T\n" +
+	"\tImageType\x12\x1a\n" +
+	"\x16IMAGE_TYPE_UNSPECIFIED\x10\x00\x12\x13\n" +
+	"\x0fIMAGE_TYPE_APPS\x10\x01\x12\x16\n" +
+	"\x12IMAGE_TYPE_DISTROS\x10\x02\"|\n" +
+	"\x12ListImagesResponse\x12'\n" +
+	"\x06images\x18\x01 \x03(\v2\x0f.slugs.v1.ImageR\x06images\x12=\n" +
+	"\fretrieved_at\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\vretrievedAt\"\x1d\n" +
+	"\x1bGetKubernetesOptionsRequest\"\x94\x01\n" +
+	"\x1cGetKubernetesOptionsResponse\x125\n" +
+	"\aoptions\x18\x01 \x01(\v2\x1b.slugs.v1.KubernetesOptionsR\aoptions\x12=\n" +
+	"\fretrieved_at\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\vretrievedAt\"\x1d\n" +
+	"\x1bListAppInstanceSizesRequest\"\x8e\x01\n" +
+	"\x1cListAppInstanceSizesResponse\x12/\n" +
+	"\x05sizes\x18\x01 \x03(\v2\x19.slugs.v1.AppInstanceSizeR\x05sizes\x12=\n" +
+	"\fretrieved_at\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\vretrievedAt\"\x1b\n" +
+	"\x19GetDatabaseOptionsRequest\"\x8e\x01\n" +
+	"\x1aGetDatabaseOptionsResponse\x121\n" +
+	"\aoptions\x18\x01 \x01(\v2\x17.google.protobuf.StructR\aoptions\x12=\n" +
+	"\fretrieved_at\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\vretrievedAt\"\x15\n" +
+	"\x13WatchChangesRequest\"a\n" +
+	"\vFieldChange\x12(\n" +
+	"\x03old\x18\x01 \x01(\v2\x16.google.protobuf.ValueR\x03old\x12(\n" +
+	"\x03new\x18\x02 \x01(\v2\x16.google.protobuf.ValueR\x03new\"\xd8\x01\n" +
+	"\x06Change\x12\x1a\n" +
+	"\bresource\x18\x01 \x01(\tR\bresource\x12\x12\n" +
+	"\x04slug\x18\x02 \x01(\tR\x04slug\x12\x16\n" +
+	"\x06action\x18\x03 \x01(\tR\x06action\x124\n" +
+	"\x06fields\x18\x04 \x03(\v2\x1c.slugs.v1.Change.FieldsEntryR\x06fields\x1aP\n" +
+	"\vFieldsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12+\n" +
+	"\x05value\x18\x02 \x01(\v2\x15.slugs.v1.FieldChangeR\x05value:\x028\x01\"\x8f\x01\n" +
+	"\x12CatalogChangeEvent\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12=\n" +
+	"\fretrieved_at\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\vretrievedAt\x12*\n" +
+	"\achanges\x18\x03 \x03(\v2\x10.slugs.v1.ChangeR\achanges2\xe7\x04\n" +
+	"\fSlugsService\x12D\n" +
+	"\tListSizes\x12\x1a.slugs.v1.ListSizesRequest\x1a\x1b.slugs.v1.ListSizesResponse\x12J\n" +
+	"\vListRegions\x12\x1c.slugs.v1.ListRegionsRequest\x1a\x1d.slugs.v1.ListRegionsResponse\x12G\n" +
+	"\n" +
+	"ListImages\x12\x1b.slugs.v1.ListImagesRequest\x1a\x1c.slugs.v1.ListImagesResponse\x12e\n" +
+	"\x14GetKubernetesOptions\x12%.slugs.v1.GetKubernetesOptionsRequest\x1a&.slugs.v1.GetKubernetesOptionsResponse\x12e\n" +
+	"\x14ListAppInstanceSizes\x12%.slugs.v1.ListAppInstanceSizesRequest\x1a&.slugs.v1.ListAppInstanceSizesResponse\x12_\n" +
+	"\x12GetDatabaseOptions\x12#.slugs.v1.GetDatabaseOptionsRequest\x1a$.slugs.v1.GetDatabaseOptionsResponse\x12M\n" +
+	"\fWatchChanges\x12\x1d.slugs.v1.WatchChangesRequest\x1a\x1c.slugs.v1.CatalogChangeEvent0\x01BDZBgithub.com/andrewsomething/do-api-slugs/api/proto/slugs/v1;slugsv1b\x06proto3"
+
+var (
+	file_slugs_v1_slugs_proto_rawDescOnce sync.Once
+	file_slugs_v1_slugs_proto_rawDescData []byte
+)
+
+func file_slugs_v1_slugs_proto_rawDescGZIP() []byte {
+	file_slugs_v1_slugs_proto_rawDescOnce.Do(func() {
+		file_slugs_v1_slugs_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_slugs_v1_slugs_proto_rawDesc), len(file_slugs_v1_slugs_proto_rawDesc)))
+	})
+	return file_slugs_v1_slugs_proto_rawDescData
+}
+
+var file_slugs_v1_slugs_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_slugs_v1_slugs_proto_msgTypes = make([]protoimpl.MessageInfo, 25)
+var file_slugs_v1_slugs_proto_goTypes = []any{
+	(ListImagesRequest_ImageType)(0),     // 0: slugs.v1.ListImagesRequest.ImageType
+	(*Size)(nil),                         // 1: slugs.v1.Size
+	(*Region)(nil),                       // 2: slugs.v1.Region
+	(*Image)(nil),                        // 3: slugs.v1.Image
+	(*KubernetesVersion)(nil),            // 4: slugs.v1.KubernetesVersion
+	(*KubernetesRegion)(nil),             // 5: slugs.v1.KubernetesRegion
+	(*KubernetesNodeSize)(nil),           // 6: slugs.v1.KubernetesNodeSize
+	(*KubernetesOptions)(nil),            // 7: slugs.v1.KubernetesOptions
+	(*AppInstanceSize)(nil),              // 8: slugs.v1.AppInstanceSize
+	(*ListSizesRequest)(nil),             // 9: slugs.v1.ListSizesRequest
+	(*ListSizesResponse)(nil),            // 10: slugs.v1.ListSizesResponse
+	(*ListRegionsRequest)(nil),           // 11: slugs.v1.ListRegionsRequest
+	(*ListRegionsResponse)(nil),          // 12: slugs.v1.ListRegionsResponse
+	(*ListImagesRequest)(nil),            // 13: slugs.v1.ListImagesRequest
+	(*ListImagesResponse)(nil),           // 14: slugs.v1.ListImagesResponse
+	(*GetKubernetesOptionsRequest)(nil),  // 15: slugs.v1.GetKubernetesOptionsRequest
+	(*GetKubernetesOptionsResponse)(nil), // 16: slugs.v1.GetKubernetesOptionsResponse
+	(*ListAppInstanceSizesRequest)(nil),  // 17: slugs.v1.ListAppInstanceSizesRequest
+	(*ListAppInstanceSizesResponse)(nil), // 18: slugs.v1.ListAppInstanceSizesResponse
+	(*GetDatabaseOptionsRequest)(nil),    // 19: slugs.v1.GetDatabaseOptionsRequest
+	(*GetDatabaseOptionsResponse)(nil),   // 20: slugs.v1.GetDatabaseOptionsResponse
+	(*WatchChangesRequest)(nil),          // 21: slugs.v1.WatchChangesRequest
+	(*FieldChange)(nil),                  // 22: slugs.v1.FieldChange
+	(*Change)(nil),                       // 23: slugs.v1.Change
+	(*CatalogChangeEvent)(nil),           // 24: slugs.v1.CatalogChangeEvent
+	nil,                                  // 25: slugs.v1.Change.FieldsEntry
+	(*timestamppb.Timestamp)(nil),        // 26: google.protobuf.Timestamp
+	(*structpb.Struct)(nil),              // 27: google.protobuf.Struct
+	(*structpb.Value)(nil),               // 28: google.protobuf.Value
+}
+var file_slugs_v1_slugs_proto_depIdxs = []int32{
+	4,  // 0: slugs.v1.KubernetesOptions.versions:type_name -> slugs.v1.KubernetesVersion
+	5,  // 1: slugs.v1.KubernetesOptions.regions:type_name -> slugs.v1.KubernetesRegion
+	6,  // 2: slugs.v1.KubernetesOptions.sizes:type_name -> slugs.v1.KubernetesNodeSize
+	1,  // 3: slugs.v1.ListSizesResponse.sizes:type_name -> slugs.v1.Size
+	26, // 4: slugs.v1.ListSizesResponse.retrieved_at:type_name -> google.protobuf.Timestamp
+	2,  // 5: slugs.v1.ListRegionsResponse.regions:type_name -> slugs.v1.Region
+	26, // 6: slugs.v1.ListRegionsResponse.retrieved_at:type_name -> google.protobuf.Timestamp
+	0,  // 7: slugs.v1.ListImagesRequest.type:type_name -> slugs.v1.ListImagesRequest.ImageType
+	3,  // 8: slugs.v1.ListImagesResponse.images:type_name -> slugs.v1.Image
+	26, // 9: slugs.v1.ListImagesResponse.retrieved_at:type_name -> google.protobuf.Timestamp
+	7,  // 10: slugs.v1.GetKubernetesOptionsResponse.options:type_name -> slugs.v1.KubernetesOptions
+	26, // 11: slugs.v1.GetKubernetesOptionsResponse.retrieved_at:type_name -> google.protobuf.Timestamp
+	8,  // 12: slugs.v1.ListAppInstanceSizesResponse.sizes:type_name -> slugs.v1.AppInstanceSize
+	26, // 13: slugs.v1.ListAppInstanceSizesResponse.retrieved_at:type_name -> google.protobuf.Timestamp
+	27, // 14: slugs.v1.GetDatabaseOptionsResponse.options:type_name -> google.protobuf.Struct
+	26, // 15: slugs.v1.GetDatabaseOptionsResponse.retrieved_at:type_name -> google.protobuf.Timestamp
+	28, // 16: slugs.v1.FieldChange.old:type_name -> google.protobuf.Value
+	28, // 17: slugs.v1.FieldChange.new:type_name -> google.protobuf.Value
+	25, // 18: slugs.v1.Change.fields:type_name -> slugs.v1.Change.FieldsEntry
+	26, // 19: slugs.v1.CatalogChangeEvent.retrieved_at:type_name -> google.protobuf.Timestamp
+	23, // 20: slugs.v1.CatalogChangeEvent.changes:type_name -> slugs.v1.Change
+	22, // 21: slugs.v1.Change.FieldsEntry.value:type_name -> slugs.v1.FieldChange
+	9,  // 22: slugs.v1.SlugsService.ListSizes:input_type -> slugs.v1.ListSizesRequest
+	11, // 23: slugs.v1.SlugsService.ListRegions:input_type -> slugs.v1.ListRegionsRequest
+	13, // 24: slugs.v1.SlugsService.ListImages:input_type -> slugs.v1.ListImagesRequest
+	15, // 25: slugs.v1.SlugsService.GetKubernetesOptions:input_type -> slugs.v1.GetKubernetesOptionsRequest
+	17, // 26: slugs.v1.SlugsService.ListAppInstanceSizes:input_type -> slugs.v1.ListAppInstanceSizesRequest
+	19, // 27: slugs.v1.SlugsService.GetDatabaseOptions:input_type -> slugs.v1.GetDatabaseOptionsRequest
+	21, // 28: slugs.v1.SlugsService.WatchChanges:input_type -> slugs.v1.WatchChangesRequest
+	10, // 29: slugs.v1.SlugsService.ListSizes:output_type -> slugs.v1.ListSizesResponse
+	12, // 30: slugs.v1.SlugsService.ListRegions:output_type -> slugs.v1.ListRegionsResponse
+	14, // 31: slugs.v1.SlugsService.ListImages:output_type -> slugs.v1.ListImagesResponse
+	16, // 32: slugs.v1.SlugsService.GetKubernetesOptions:output_type -> slugs.v1.GetKubernetesOptionsResponse
+	18, // 33: slugs.v1.SlugsService.ListAppInstanceSizes:output_type -> slugs.v1.ListAppInstanceSizesResponse
+	20, // 34: slugs.v1.SlugsService.GetDatabaseOptions:output_type -> slugs.v1.GetDatabaseOptionsResponse
+	24, // 35: slugs.v1.SlugsService.WatchChanges:output_type -> slugs.v1.CatalogChangeEvent
+	29, // [29:36] is the sub-list for method output_type
+	22, // [22:29] is the sub-list for method input_type
+	22, // [22:22] is the sub-list for extension type_name
+	22, // [22:22] is the sub-list for extension extendee
+	0,  // [0:22] is the sub-list for field type_name
+}
+
+func init() { file_slugs_v1_slugs_proto_init() }
+func file_slugs_v1_slugs_proto_init() {
+	if File_slugs_v1_slugs_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_slugs_v1_slugs_proto_rawDesc), len(file_slugs_v1_slugs_proto_rawDesc)),
+			NumEnums:      1,
+			NumMessages:   25,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_slugs_v1_slugs_proto_goTypes,
+		DependencyIndexes: file_slugs_v1_slugs_proto_depIdxs,
+		EnumInfos:         file_slugs_v1_slugs_proto_enumTypes,
+		MessageInfos:      file_slugs_v1_slugs_proto_msgTypes,
+	}.Build()
+	File_slugs_v1_slugs_proto = out.File
+	file_slugs_v1_slugs_proto_goTypes = nil
+	file_slugs_v1_slugs_proto_depIdxs = nil
+}
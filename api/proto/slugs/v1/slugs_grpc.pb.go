@@ -0,0 +1,363 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.6.2
+// - protoc             (unknown)
+// source: slugs/v1/slugs.proto
+
+package slugsv1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	SlugsService_ListSizes_FullMethodName            = "/slugs.v1.SlugsService/ListSizes"
+	SlugsService_ListRegions_FullMethodName          = "/slugs.v1.SlugsService/ListRegions"
+	SlugsService_ListImages_FullMethodName           = "/slugs.v1.SlugsService/ListImages"
+	SlugsService_GetKubernetesOptions_FullMethodName = "/slugs.v1.SlugsService/GetKubernetesOptions"
+	SlugsService_ListAppInstanceSizes_FullMethodName = "/slugs.v1.SlugsService/ListAppInstanceSizes"
+	SlugsService_GetDatabaseOptions_FullMethodName   = "/slugs.v1.SlugsService/GetDatabaseOptions"
+	SlugsService_WatchChanges_FullMethodName         = "/slugs.v1.SlugsService/WatchChanges"
+)
+
+// SlugsServiceClient is the client API for SlugsService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// SlugsService serves the same catalog as the HTTP API. Every response
+// carries the time the catalog was retrieved from DigitalOcean.
+type SlugsServiceClient interface {
+	ListSizes(ctx context.Context, in *ListSizesRequest, opts ...grpc.CallOption) (*ListSizesResponse, error)
+	ListRegions(ctx context.Context, in *ListRegionsRequest, opts ...grpc.CallOption) (*ListRegionsResponse, error)
+	ListImages(ctx context.Context, in *ListImagesRequest, opts ...grpc.CallOption) (*ListImagesResponse, error)
+	GetKubernetesOptions(ctx context.Context, in *GetKubernetesOptionsRequest, opts ...grpc.CallOption) (*GetKubernetesOptionsResponse, error)
+	ListAppInstanceSizes(ctx context.Context, in *ListAppInstanceSizesRequest, opts ...grpc.CallOption) (*ListAppInstanceSizesResponse, error)
+	GetDatabaseOptions(ctx context.Context, in *GetDatabaseOptionsRequest, opts ...grpc.CallOption) (*GetDatabaseOptionsResponse, error)
+	// WatchChanges streams an event whenever a background refresh finds that
+	// the catalog has changed.
+	WatchChanges(ctx context.Context, in *WatchChangesRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[CatalogChangeEvent], error)
+}
+
+type slugsServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewSlugsServiceClient(cc grpc.ClientConnInterface) SlugsServiceClient {
+	return &slugsServiceClient{cc}
+}
+
+func (c *slugsServiceClient) ListSizes(ctx context.Context, in *ListSizesRequest, opts ...grpc.CallOption) (*ListSizesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListSizesResponse)
+	err := c.cc.Invoke(ctx, SlugsService_ListSizes_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *slugsServiceClient) ListRegions(ctx context.Context, in *ListRegionsRequest, opts ...grpc.CallOption) (*ListRegionsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListRegionsResponse)
+	err := c.cc.Invoke(ctx, SlugsService_ListRegions_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *slugsServiceClient) ListImages(ctx context.Context, in *ListImagesRequest, opts ...grpc.CallOption) (*ListImagesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListImagesResponse)
+	err := c.cc.Invoke(ctx, SlugsService_ListImages_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *slugsServiceClient) GetKubernetesOptions(ctx context.Context, in *GetKubernetesOptionsRequest, opts ...grpc.CallOption) (*GetKubernetesOptionsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetKubernetesOptionsResponse)
+	err := c.cc.Invoke(ctx, SlugsService_GetKubernetesOptions_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *slugsServiceClient) ListAppInstanceSizes(ctx context.Context, in *ListAppInstanceSizesRequest, opts ...grpc.CallOption) (*ListAppInstanceSizesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListAppInstanceSizesResponse)
+	err := c.cc.Invoke(ctx, SlugsService_ListAppInstanceSizes_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *slugsServiceClient) GetDatabaseOptions(ctx context.Context, in *GetDatabaseOptionsRequest, opts ...grpc.CallOption) (*GetDatabaseOptionsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetDatabaseOptionsResponse)
+	err := c.cc.Invoke(ctx, SlugsService_GetDatabaseOptions_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *slugsServiceClient) WatchChanges(ctx context.Context, in *WatchChangesRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[CatalogChangeEvent], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &SlugsService_ServiceDesc.Streams[0], SlugsService_WatchChanges_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[WatchChangesRequest, CatalogChangeEvent]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type SlugsService_WatchChangesClient = grpc.ServerStreamingClient[CatalogChangeEvent]
+
+// SlugsServiceServer is the server API for SlugsService service.
+// All implementations must embed UnimplementedSlugsServiceServer
+// for forward compatibility.
+//
+// SlugsService serves the same catalog as the HTTP API. Every response
+// carries the time the catalog was retrieved from DigitalOcean.
+type SlugsServiceServer interface {
+	ListSizes(context.Context, *ListSizesRequest) (*ListSizesResponse, error)
+	ListRegions(context.Context, *ListRegionsRequest) (*ListRegionsResponse, error)
+	ListImages(context.Context, *ListImagesRequest) (*ListImagesResponse, error)
+	GetKubernetesOptions(context.Context, *GetKubernetesOptionsRequest) (*GetKubernetesOptionsResponse, error)
+	ListAppInstanceSizes(context.Context, *ListAppInstanceSizesRequest) (*ListAppInstanceSizesResponse, error)
+	GetDatabaseOptions(context.Context, *GetDatabaseOptionsRequest) (*GetDatabaseOptionsResponse, error)
+	// WatchChanges streams an event whenever a background refresh finds that
+	// the catalog has changed.
+	WatchChanges(*WatchChangesRequest, grpc.ServerStreamingServer[CatalogChangeEvent]) error
+	mustEmbedUnimplementedSlugsServiceServer()
+}
+
+// UnimplementedSlugsServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedSlugsServiceServer struct{}
+
+func (UnimplementedSlugsServiceServer) ListSizes(context.Context, *ListSizesRequest) (*ListSizesResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ListSizes not implemented")
+}
+func (UnimplementedSlugsServiceServer) ListRegions(context.Context, *ListRegionsRequest) (*ListRegionsResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ListRegions not implemented")
+}
+func (UnimplementedSlugsServiceServer) ListImages(context.Context, *ListImagesRequest) (*ListImagesResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ListImages not implemented")
+}
+func (UnimplementedSlugsServiceServer) GetKubernetesOptions(context.Context, *GetKubernetesOptionsRequest) (*GetKubernetesOptionsResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetKubernetesOptions not implemented")
+}
+func (UnimplementedSlugsServiceServer) ListAppInstanceSizes(context.Context, *ListAppInstanceSizesRequest) (*ListAppInstanceSizesResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ListAppInstanceSizes not implemented")
+}
+func (UnimplementedSlugsServiceServer) GetDatabaseOptions(context.Context, *GetDatabaseOptionsRequest) (*GetDatabaseOptionsResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetDatabaseOptions not implemented")
+}
+func (UnimplementedSlugsServiceServer) WatchChanges(*WatchChangesRequest, grpc.ServerStreamingServer[CatalogChangeEvent]) error {
+	return status.Error(codes.Unimplemented, "method WatchChanges not implemented")
+}
+func (UnimplementedSlugsServiceServer) mustEmbedUnimplementedSlugsServiceServer() {}
+func (UnimplementedSlugsServiceServer) testEmbeddedByValue()                      {}
+
+// UnsafeSlugsServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to SlugsServiceServer will
+// result in compilation errors.
+type UnsafeSlugsServiceServer interface {
+	mustEmbedUnimplementedSlugsServiceServer()
+}
+
+func RegisterSlugsServiceServer(s grpc.ServiceRegistrar, srv SlugsServiceServer) {
+	// If the following call panics, it indicates UnimplementedSlugsServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&SlugsService_ServiceDesc, srv)
+}
+
+func _SlugsService_ListSizes_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListSizesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SlugsServiceServer).ListSizes(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SlugsService_ListSizes_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SlugsServiceServer).ListSizes(ctx, req.(*ListSizesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SlugsService_ListRegions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListRegionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SlugsServiceServer).ListRegions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SlugsService_ListRegions_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SlugsServiceServer).ListRegions(ctx, req.(*ListRegionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SlugsService_ListImages_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListImagesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SlugsServiceServer).ListImages(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SlugsService_ListImages_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SlugsServiceServer).ListImages(ctx, req.(*ListImagesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SlugsService_GetKubernetesOptions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetKubernetesOptionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SlugsServiceServer).GetKubernetesOptions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SlugsService_GetKubernetesOptions_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SlugsServiceServer).GetKubernetesOptions(ctx, req.(*GetKubernetesOptionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SlugsService_ListAppInstanceSizes_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListAppInstanceSizesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SlugsServiceServer).ListAppInstanceSizes(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SlugsService_ListAppInstanceSizes_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SlugsServiceServer).ListAppInstanceSizes(ctx, req.(*ListAppInstanceSizesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SlugsService_GetDatabaseOptions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetDatabaseOptionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SlugsServiceServer).GetDatabaseOptions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SlugsService_GetDatabaseOptions_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SlugsServiceServer).GetDatabaseOptions(ctx, req.(*GetDatabaseOptionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SlugsService_WatchChanges_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchChangesRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(SlugsServiceServer).WatchChanges(m, &grpc.GenericServerStream[WatchChangesRequest, CatalogChangeEvent]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type SlugsService_WatchChangesServer = grpc.ServerStreamingServer[CatalogChangeEvent]
+
+// SlugsService_ServiceDesc is the grpc.ServiceDesc for SlugsService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var SlugsService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "slugs.v1.SlugsService",
+	HandlerType: (*SlugsServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ListSizes",
+			Handler:    _SlugsService_ListSizes_Handler,
+		},
+		{
+			MethodName: "ListRegions",
+			Handler:    _SlugsService_ListRegions_Handler,
+		},
+		{
+			MethodName: "ListImages",
+			Handler:    _SlugsService_ListImages_Handler,
+		},
+		{
+			MethodName: "GetKubernetesOptions",
+			Handler:    _SlugsService_GetKubernetesOptions_Handler,
+		},
+		{
+			MethodName: "ListAppInstanceSizes",
+			Handler:    _SlugsService_ListAppInstanceSizes_Handler,
+		},
+		{
+			MethodName: "GetDatabaseOptions",
+			Handler:    _SlugsService_GetDatabaseOptions_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "WatchChanges",
+			Handler:       _SlugsService_WatchChanges_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "slugs/v1/slugs.proto",
+}
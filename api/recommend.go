@@ -0,0 +1,184 @@
+package main
+
+import (
+	"net/http"
+	"sort"
+	"strconv"
+
+	"github.com/andrewsomething/do-api-slugs/api/internal/catalog"
+	"github.com/digitalocean/godo"
+)
+
+const (
+	defaultRecommendLimit = 10
+	maxRecommendLimit     = 100
+
+	rankByPrice          = "price"
+	rankByPricePerVcpu   = "price_per_vcpu"
+	rankByPricePerGB     = "price_per_gb"
+	megabytesPerGigabyte = 1024
+)
+
+// sizeRequirements are the constraints accepted by /recommend/sizes. Zero
+// values are unconstrained.
+type sizeRequirements struct {
+	MinVcpus        int
+	MinMemoryGB     float64
+	GPU             *bool
+	Region          string
+	MaxPriceMonthly float64
+}
+
+type sizeRecommendation struct {
+	godo.Size
+	PricePerVcpu float64 `json:"price_per_vcpu"`
+	PricePerGB   float64 `json:"price_per_gb"`
+}
+
+type sizeRecommendationsResponse struct {
+	Recommendations []sizeRecommendation `json:"recommendations"`
+	RetrievedAt     string               `json:"retrieved_at"`
+}
+
+// recommendSizes handles /recommend/sizes. Supported query parameters are
+// vcpus (minimum), memory (minimum, in GB), gpu (true or false), region,
+// budget (maximum monthly price), rank (price, price_per_vcpu or
+// price_per_gb) and limit.
+func (h *handler) recommendSizes(w http.ResponseWriter, r *http.Request) {
+	reqs, rank, limit, ok := parseSizeRequirements(r)
+	if !ok {
+		writeJSONError(w, http.StatusBadRequest)
+		return
+	}
+
+	cat, err := h.catalogFor(r.Context(), catalog.ResourceSizes, catalog.ResourceRegions)
+	if err != nil {
+		h.writeUpstreamError(w, r, err)
+		return
+	}
+	if reqs.Region != "" && !regionExists(cat, reqs.Region) {
+		writeJSONError(w, http.StatusNotFound)
+		return
+	}
+
+	recommendations := recommend(cat, reqs, rank)
+	if len(recommendations) > limit {
+		recommendations = recommendations[:limit]
+	}
+
+	resp := sizeRecommendationsResponse{
+		Recommendations: recommendations,
+		RetrievedAt:     retrievedAt(cat, catalog.ResourceSizes, catalog.ResourceRegions),
+	}
+
+	writeResponse(w, r, resp)
+}
+
+func parseSizeRequirements(r *http.Request) (sizeRequirements, string, int, bool) {
+	q := r.URL.Query()
+	var reqs sizeRequirements
+	var err error
+
+	if v := q.Get("vcpus"); v != "" {
+		if reqs.MinVcpus, err = strconv.Atoi(v); err != nil {
+			return reqs, "", 0, false
+		}
+	}
+	if v := q.Get("memory"); v != "" {
+		if reqs.MinMemoryGB, err = strconv.ParseFloat(v, 64); err != nil {
+			return reqs, "", 0, false
+		}
+	}
+	if v := q.Get("gpu"); v != "" {
+		gpu, err := strconv.ParseBool(v)
+		if err != nil {
+			return reqs, "", 0, false
+		}
+		reqs.GPU = &gpu
+	}
+	if v := q.Get("budget"); v != "" {
+		if reqs.MaxPriceMonthly, err = strconv.ParseFloat(v, 64); err != nil {
+			return reqs, "", 0, false
+		}
+	}
+	reqs.Region = q.Get("region")
+
+	rank := q.Get("rank")
+	switch rank {
+	case "":
+		rank = rankByPrice
+	case rankByPrice, rankByPricePerVcpu, rankByPricePerGB:
+	default:
+		return reqs, "", 0, false
+	}
+
+	limit := defaultRecommendLimit
+	if v := q.Get("limit"); v != "" {
+		limit, err = strconv.Atoi(v)
+		if err != nil || limit < 1 || limit > maxRecommendLimit {
+			return reqs, "", 0, false
+		}
+	}
+
+	return reqs, rank, limit, true
+}
+
+// availableRegionSizes returns the sizes offered in region according to the
+// region's own list, or none if the region isn't available.
+func availableRegionSizes(cat *catalog.Catalog, region string) []string {
+	for _, r := range cat.Regions {
+		if r.Slug == region && r.Available {
+			return r.Sizes
+		}
+	}
+
+	return nil
+}
+
+// recommend returns the available sizes meeting reqs, cheapest first by the
+// given ranking. Region availability is taken from the region's own list of
+// sizes.
+func recommend(cat *catalog.Catalog, reqs sizeRequirements, rank string) []sizeRecommendation {
+	regionSizes := availableRegionSizes(cat, reqs.Region)
+
+	list := []sizeRecommendation{}
+	for _, s := range cat.Sizes {
+		memoryGB := float64(s.Memory) / megabytesPerGigabyte
+		switch {
+		case !s.Available,
+			s.Vcpus < reqs.MinVcpus,
+			memoryGB < reqs.MinMemoryGB,
+			reqs.GPU != nil && hasGPU(s) != *reqs.GPU,
+			reqs.MaxPriceMonthly > 0 && s.PriceMonthly > reqs.MaxPriceMonthly,
+			reqs.Region != "" && !contains(regionSizes, s.Slug):
+			continue
+		}
+
+		rec := sizeRecommendation{Size: s}
+		if s.Vcpus > 0 {
+			rec.PricePerVcpu = roundPrice(s.PriceMonthly/float64(s.Vcpus), 2)
+		}
+		if memoryGB > 0 {
+			rec.PricePerGB = roundPrice(s.PriceMonthly/memoryGB, 2)
+		}
+		list = append(list, rec)
+	}
+
+	key := func(r sizeRecommendation) float64 {
+		switch rank {
+		case rankByPricePerVcpu:
+			return r.PricePerVcpu
+		case rankByPricePerGB:
+			return r.PricePerGB
+		}
+		return r.PriceMonthly
+	}
+	sort.SliceStable(list, func(i, j int) bool {
+		if key(list[i]) != key(list[j]) {
+			return key(list[i]) < key(list[j])
+		}
+		return list[i].PriceMonthly < list[j].PriceMonthly
+	})
+
+	return list
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/andrewsomething/do-api-slugs/api/internal/catalog"
+	"github.com/digitalocean/godo"
+)
+
+func TestRecommend(t *testing.T) {
+	cat := &catalog.Catalog{
+		Sizes: []godo.Size{
+			{Slug: "s-1vcpu-1gb", Vcpus: 1, Memory: 1024, PriceMonthly: 6, Available: true},
+			{Slug: "s-2vcpu-2gb", Vcpus: 2, Memory: 2048, PriceMonthly: 18, Available: true},
+			{Slug: "c-2", Vcpus: 2, Memory: 4096, PriceMonthly: 42, Available: true},
+			{Slug: "m-2vcpu-16gb", Vcpus: 2, Memory: 16384, PriceMonthly: 84, Available: true},
+			{Slug: "gpu-h100x1", Vcpus: 20, Memory: 245760, PriceMonthly: 2500, Available: true, GPUInfo: &godo.GPUInfo{Count: 1}},
+			{Slug: "s-retired", Vcpus: 1, Memory: 1024, PriceMonthly: 5, Available: false},
+		},
+		Regions: []godo.Region{
+			{Slug: "nyc1", Available: true, Sizes: []string{"s-1vcpu-1gb", "c-2", "gpu-h100x1"}},
+			{Slug: "sfo1", Available: false, Sizes: []string{"s-1vcpu-1gb"}},
+		},
+	}
+	yes, no := true, false
+
+	tests := []struct {
+		name string
+		reqs sizeRequirements
+		rank string
+		want []string
+	}{
+		{name: "cheapest first", rank: rankByPrice, want: []string{"s-1vcpu-1gb", "s-2vcpu-2gb", "c-2", "m-2vcpu-16gb", "gpu-h100x1"}},
+		{name: "minimums", reqs: sizeRequirements{MinVcpus: 2, MinMemoryGB: 4}, rank: rankByPrice, want: []string{"c-2", "m-2vcpu-16gb", "gpu-h100x1"}},
+		{name: "fractional memory", reqs: sizeRequirements{MinMemoryGB: 1.5}, rank: rankByPrice, want: []string{"s-2vcpu-2gb", "c-2", "m-2vcpu-16gb", "gpu-h100x1"}},
+		{name: "gpu", reqs: sizeRequirements{GPU: &yes}, rank: rankByPrice, want: []string{"gpu-h100x1"}},
+		{name: "no gpu within budget", reqs: sizeRequirements{GPU: &no, MaxPriceMonthly: 42}, rank: rankByPrice, want: []string{"s-1vcpu-1gb", "s-2vcpu-2gb", "c-2"}},
+		{name: "region", reqs: sizeRequirements{Region: "nyc1"}, rank: rankByPrice, want: []string{"s-1vcpu-1gb", "c-2", "gpu-h100x1"}},
+		{name: "unavailable region", reqs: sizeRequirements{Region: "sfo1"}, rank: rankByPrice, want: []string{}},
+		// Per vCPU: 6, 9, 21, 42, 125.
+		{name: "price per vcpu", reqs: sizeRequirements{MaxPriceMonthly: 100}, rank: rankByPricePerVcpu, want: []string{"s-1vcpu-1gb", "s-2vcpu-2gb", "c-2", "m-2vcpu-16gb"}},
+		// Per GB: 6, 9, 10.5, 5.25, 10.42.
+		{name: "price per gb", rank: rankByPricePerGB, want: []string{"m-2vcpu-16gb", "s-1vcpu-1gb", "s-2vcpu-2gb", "gpu-h100x1", "c-2"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := []string{}
+			for _, r := range recommend(cat, tt.reqs, tt.rank) {
+				got = append(got, r.Slug)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseSizeRequirements(t *testing.T) {
+	yes := true
+
+	tests := []struct {
+		query  string
+		reqs   sizeRequirements
+		rank   string
+		limit  int
+		wantOK bool
+	}{
+		{query: "", rank: rankByPrice, limit: defaultRecommendLimit, wantOK: true},
+		{
+			query:  "vcpus=2&memory=3.5&gpu=true&region=nyc1&budget=50&rank=price_per_gb&limit=3",
+			reqs:   sizeRequirements{MinVcpus: 2, MinMemoryGB: 3.5, GPU: &yes, Region: "nyc1", MaxPriceMonthly: 50},
+			rank:   rankByPricePerGB,
+			limit:  3,
+			wantOK: true,
+		},
+		{query: "vcpus=two"},
+		{query: "memory=lots"},
+		{query: "gpu=maybe"},
+		{query: "budget=free"},
+		{query: "rank=speed"},
+		{query: "limit=0"},
+		{query: "limit=101"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/recommend/sizes?"+tt.query, nil)
+			reqs, rank, limit, ok := parseSizeRequirements(r)
+			if ok != tt.wantOK {
+				t.Fatalf("got ok %t, want %t", ok, tt.wantOK)
+			}
+			if ok && (!reflect.DeepEqual(reqs, tt.reqs) || rank != tt.rank || limit != tt.limit) {
+				t.Errorf("got %+v, %q, %d, want %+v, %q, %d", reqs, rank, limit, tt.reqs, tt.rank, tt.limit)
+			}
+		})
+	}
+}
@@ -0,0 +1,370 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/andrewsomething/do-api-slugs/api/internal/catalog"
+	"github.com/digitalocean/godo"
+)
+
+const (
+	defaultRefreshInterval = 15 * time.Minute
+	// refreshTimeout bounds background refreshes, which have no caller
+	// deadline to inherit.
+	refreshTimeout = 2 * time.Minute
+	// refreshRateReserve is the fraction of the token's rate limit kept
+	// for requests that go upstream. Background refreshes are postponed
+	// rather than dip into it.
+	refreshRateReserve = 0.1
+	subscriberBuffer   = 8
+)
+
+// refreshEvent is passed to listeners after every successful refresh of a
+// resource. Current differs from Previous only in that resource. Previous is
+// nil for the first refresh, in which case Changes is empty.
+type refreshEvent struct {
+	Previous *catalog.Catalog
+	Current  *catalog.Catalog
+	Changes  []change
+}
+
+// refresher periodically retrieves the catalog in the background and keeps
+// the most recent copy along with the differences from the one before it.
+// Each resource is refreshed on its own, so one failing upstream call only
+// leaves that resource stale.
+type refresher struct {
+	client   *godo.Client
+	interval time.Duration
+
+	mu        sync.RWMutex
+	current   *catalog.Catalog
+	listeners []func(refreshEvent)
+	// subscribers receive events on channels for as long as they are
+	// subscribed, unlike listeners which are registered for good.
+	subscribers map[chan refreshEvent]struct{}
+	resources   map[string]*resourceState
+}
+
+// resourceState is the refresh state of one resource in the catalog.
+type resourceState struct {
+	resource catalog.Resource
+	// inflight is the refresh in progress, if any.
+	inflight *refreshCall
+	// fromSnapshot is set while the resource was loaded from a snapshot
+	// rather than retrieved by a refresh.
+	fromSnapshot bool
+	// lastErr is the error from the most recent refresh, if it failed.
+	lastErr error
+}
+
+// resourceStatus describes the cached copy of a resource. RetrievedAt is zero
+// if the resource hasn't been retrieved yet.
+type resourceStatus struct {
+	Name         string
+	RetrievedAt  time.Time
+	FromSnapshot bool
+	LastErr      error
+}
+
+// refreshCall is a refresh shared by every caller that asks for one while it
+// is in progress. The fetch is cancelled only once every waiter has given
+// up, so one client disconnecting doesn't fail the others.
+type refreshCall struct {
+	done    chan struct{}
+	err     error
+	waiters int
+	cancel  context.CancelFunc
+	// info collects the DigitalOcean request IDs of the fetch, which are
+	// passed on to every waiter.
+	info *requestInfo
+}
+
+func newRefresher(client *godo.Client, interval time.Duration) *refresher {
+	r := &refresher{
+		client:      client,
+		interval:    interval,
+		subscribers: make(map[chan refreshEvent]struct{}),
+		resources:   make(map[string]*resourceState, len(catalog.Resources)),
+	}
+	for _, res := range catalog.Resources {
+		r.resources[res.Name] = &resourceState{resource: res}
+	}
+
+	return r
+}
+
+// seed makes cat the current catalog until the first refresh succeeds, so a
+// snapshot can be served if the API is unreachable at startup. It must be
+// called before run.
+func (r *refresher) seed(cat *catalog.Catalog) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.current = cat
+	for name, state := range r.resources {
+		_, state.fromSnapshot = cat.ResourceRetrievedAt(name)
+	}
+}
+
+// onRefresh registers fn to be called after each successful refresh. It must
+// be called before run.
+func (r *refresher) onRefresh(fn func(refreshEvent)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.listeners = append(r.listeners, fn)
+}
+
+// subscribe returns a channel that receives every subsequent refresh event,
+// and a function that ends the subscription. Events are dropped for
+// subscribers that fall behind rather than holding up the refresh.
+func (r *refresher) subscribe() (<-chan refreshEvent, func()) {
+	ch := make(chan refreshEvent, subscriberBuffer)
+	r.mu.Lock()
+	r.subscribers[ch] = struct{}{}
+	r.mu.Unlock()
+
+	return ch, func() {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		delete(r.subscribers, ch)
+	}
+}
+
+// run refreshes every resource each interval. Failures are logged by fetch.
+func (r *refresher) run() {
+	for {
+		ctx, cancel := context.WithTimeout(context.Background(), refreshTimeout)
+		r.refresh(ctx)
+		cancel()
+		time.Sleep(r.delay())
+	}
+}
+
+// delay returns how long to wait before the next refresh: the refresh
+// interval, or until the rate limit resets if less than refreshRateReserve of
+// it remains.
+func (r *refresher) delay() time.Duration {
+	rate := r.client.GetRate()
+	if rate.Limit == 0 || float64(rate.Remaining) >= float64(rate.Limit)*refreshRateReserve {
+		return r.interval
+	}
+	wait := time.Until(rate.Reset.Time)
+	if wait <= r.interval {
+		return r.interval
+	}
+	slog.Warn("postponing refresh until rate limit resets",
+		"remaining", rate.Remaining,
+		"limit", rate.Limit,
+		"wait", wait.Round(time.Second),
+	)
+
+	return wait
+}
+
+// refresh retrieves every resource in the catalog.
+func (r *refresher) refresh(ctx context.Context) error {
+	return r.refreshResources(ctx, catalog.ResourceNames()...)
+}
+
+// refreshResources retrieves the named resources concurrently, returning
+// the errors of those that failed.
+func (r *refresher) refreshResources(ctx context.Context, names ...string) error {
+	errs := make([]error, len(names))
+	var wg sync.WaitGroup
+	for i, name := range names {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs[i] = r.refreshResource(ctx, name)
+		}()
+	}
+	wg.Wait()
+
+	return errors.Join(errs...)
+}
+
+// refreshResource retrieves the named resource, joining the refresh already
+// in progress if there is one. It returns early with ctx's error if ctx is
+// done first. The DigitalOcean request IDs of the refresh are added to ctx's
+// request.
+func (r *refresher) refreshResource(ctx context.Context, name string) error {
+	r.mu.Lock()
+	state := r.resources[name]
+	call := state.inflight
+	if call == nil {
+		// The fetch outlives the caller that started it if others are
+		// waiting for it too, so it only keeps the caller's values, and
+		// logs with its request ID.
+		info := &requestInfo{}
+		if parent := requestInfoFrom(ctx); parent != nil {
+			info.id = parent.id
+		}
+		fetchCtx := context.WithValue(context.WithoutCancel(ctx), requestInfoContextKey{}, info)
+		fetchCtx, cancel := context.WithCancel(fetchCtx)
+		call = &refreshCall{done: make(chan struct{}), cancel: cancel, info: info}
+		state.inflight = call
+		go r.fetch(fetchCtx, state, call)
+	}
+	call.waiters++
+	r.mu.Unlock()
+
+	defer r.leave(state, call)
+	select {
+	case <-call.done:
+		for _, id := range call.info.upstreamIDs() {
+			addUpstreamRequestID(ctx, id)
+		}
+		return call.err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// leave removes a waiter from call, cancelling the fetch if it was the last.
+func (r *refresher) leave(state *resourceState, call *refreshCall) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	call.waiters--
+	if call.waiters > 0 {
+		return
+	}
+	call.cancel()
+	if state.inflight == call {
+		state.inflight = nil
+	}
+}
+
+func (r *refresher) fetch(ctx context.Context, state *resourceState, call *refreshCall) {
+	defer close(call.done)
+
+	name := state.resource.Name
+	store, err := state.resource.Fetch(ctx, r.client)
+	r.mu.Lock()
+	if state.inflight == call {
+		state.inflight = nil
+	}
+	// A fetch cancelled because every caller gave up says nothing about
+	// the API, so it doesn't replace the last result.
+	if errors.Is(err, context.Canceled) {
+		r.mu.Unlock()
+		call.err = err
+		return
+	}
+	state.lastErr = err
+	if err != nil {
+		r.mu.Unlock()
+		call.err = err
+		loggerFrom(ctx).Error("refresh failed",
+			"resource", name,
+			"error", err,
+			"upstream_request_ids", call.info.upstreamIDs(),
+		)
+		return
+	}
+	state.fromSnapshot = false
+	prev := r.current
+	cat := prev.Clone()
+	store(cat)
+	r.current = cat
+	listeners := r.listeners
+	subscribers := make([]chan refreshEvent, 0, len(r.subscribers))
+	for ch := range r.subscribers {
+		subscribers = append(subscribers, ch)
+	}
+	r.mu.Unlock()
+
+	event := refreshEvent{
+		Previous: prev,
+		Current:  cat,
+		Changes:  diffCatalogs(prev, cat),
+	}
+	for _, fn := range listeners {
+		fn(event)
+	}
+	for _, ch := range subscribers {
+		select {
+		case ch <- event:
+		default:
+		}
+	}
+}
+
+// latest returns the most recently retrieved catalog, or nil if no refresh
+// has succeeded yet. Resources that haven't been retrieved are empty.
+func (r *refresher) latest() *catalog.Catalog {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.current
+}
+
+// status describes each resource in the catalog, in catalog order.
+func (r *refresher) status() []resourceStatus {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	list := make([]resourceStatus, 0, len(catalog.Resources))
+	for _, res := range catalog.Resources {
+		state := r.resources[res.Name]
+		retrievedAt, _ := r.current.ResourceRetrievedAt(res.Name)
+		list = append(list, resourceStatus{
+			Name:         res.Name,
+			RetrievedAt:  retrievedAt,
+			FromSnapshot: state.fromSnapshot,
+			LastErr:      state.lastErr,
+		})
+	}
+
+	return list
+}
+
+// catalogFor returns the catalog from the most recent background refresh
+// once each of the named resources has been retrieved, refreshing those that
+// haven't been synchronously. The refresh is bounded by the request timeout
+// as well as ctx. Each lookup counts as a cache hit or miss for its resource.
+func (h *handler) catalogFor(ctx context.Context, names ...string) (*catalog.Catalog, error) {
+	cat := h.refresher.latest()
+	var missing []string
+	for _, name := range names {
+		if _, ok := cat.ResourceRetrievedAt(name); ok {
+			catalogCacheRequests.WithLabelValues(name, "hit").Inc()
+			continue
+		}
+		catalogCacheRequests.WithLabelValues(name, "miss").Inc()
+		missing = append(missing, name)
+	}
+	if len(missing) == 0 {
+		setCacheStatus(ctx, "hit")
+		return cat, nil
+	}
+
+	setCacheStatus(ctx, "miss")
+	ctx, cancel := context.WithTimeout(ctx, h.requestTimeout)
+	defer cancel()
+	if err := h.refresher.refreshResources(ctx, missing...); err != nil {
+		return nil, err
+	}
+
+	return h.refresher.latest(), nil
+}
+
+// oldestRetrievedAt returns the time the oldest of the named resources in cat
+// was retrieved.
+func oldestRetrievedAt(cat *catalog.Catalog, names ...string) time.Time {
+	var oldest time.Time
+	for _, name := range names {
+		if at, ok := cat.ResourceRetrievedAt(name); ok && (oldest.IsZero() || at.Before(oldest)) {
+			oldest = at
+		}
+	}
+
+	return oldest
+}
+
+// retrievedAt formats oldestRetrievedAt for the retrieved_at field of
+// responses.
+func retrievedAt(cat *catalog.Catalog, names ...string) string {
+	return oldestRetrievedAt(cat, names...).Format("Mon Jan _2 15:04:05 2006 UTC")
+}
@@ -0,0 +1,322 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/andrewsomething/do-api-slugs/api/internal/catalog"
+	"github.com/digitalocean/godo"
+)
+
+// newTestClient returns a godo client that sends every request to h.
+func newTestClient(t *testing.T, h http.Handler) *godo.Client {
+	srv := httptest.NewServer(h)
+	t.Cleanup(srv.Close)
+
+	client := godo.NewClient(srv.Client())
+	client.BaseURL, _ = url.Parse(srv.URL + "/")
+
+	return client
+}
+
+// blockingUpstream answers every request with an empty object, except that
+// size listings wait for release or for the request to be cancelled.
+type blockingUpstream struct {
+	release   chan struct{}
+	started   chan struct{}
+	cancelled chan struct{}
+	sizes     atomic.Int32
+}
+
+func newBlockingUpstream() *blockingUpstream {
+	return &blockingUpstream{
+		release:   make(chan struct{}),
+		started:   make(chan struct{}, 10),
+		cancelled: make(chan struct{}, 10),
+	}
+}
+
+func (u *blockingUpstream) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path == "/v2/sizes" {
+		u.sizes.Add(1)
+		u.started <- struct{}{}
+		select {
+		case <-u.release:
+		case <-r.Context().Done():
+			u.cancelled <- struct{}{}
+			return
+		}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write([]byte("{}"))
+}
+
+// waitForWaiters waits until n callers have joined the refresh of sizes in
+// progress.
+func waitForWaiters(t *testing.T, r *refresher, n int) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		r.mu.Lock()
+		waiters := 0
+		if call := r.resources[catalog.ResourceSizes].inflight; call != nil {
+			waiters = call.waiters
+		}
+		r.mu.Unlock()
+		if waiters == n {
+			return
+		}
+		time.Sleep(time.Millisecond)
+	}
+	t.Fatalf("timed out waiting for %d waiters", n)
+}
+
+func TestRefresherCoalesces(t *testing.T) {
+	const callers = 5
+	upstream := newBlockingUpstream()
+	r := newRefresher(newTestClient(t, upstream), time.Minute)
+	var events atomic.Int32
+	r.onRefresh(func(refreshEvent) { events.Add(1) })
+
+	errs := make(chan error, callers)
+	for range callers {
+		go func() { errs <- r.refresh(context.Background()) }()
+	}
+	<-upstream.started
+	waitForWaiters(t, r, callers)
+	close(upstream.release)
+
+	for range callers {
+		if err := <-errs; err != nil {
+			t.Errorf("refresh failed: %v", err)
+		}
+	}
+	if n := upstream.sizes.Load(); n != 1 {
+		t.Errorf("sizes were fetched %d times, want once", n)
+	}
+	if n := events.Load(); n != int32(len(catalog.Resources)) {
+		t.Errorf("listeners were called %d times, want once per resource", n)
+	}
+	if r.latest() == nil {
+		t.Error("no catalog after refresh")
+	}
+}
+
+func TestRefresherCancelsOnLastWaiter(t *testing.T) {
+	upstream := newBlockingUpstream()
+	r := newRefresher(newTestClient(t, upstream), time.Minute)
+
+	ctx1, cancel1 := context.WithCancel(context.Background())
+	ctx2, cancel2 := context.WithCancel(context.Background())
+	defer cancel2()
+	errs1 := make(chan error, 1)
+	errs2 := make(chan error, 1)
+	go func() { errs1 <- r.refresh(ctx1) }()
+	go func() { errs2 <- r.refresh(ctx2) }()
+	<-upstream.started
+	waitForWaiters(t, r, 2)
+
+	// The first caller giving up leaves the fetch running for the second.
+	cancel1()
+	if err := <-errs1; !errors.Is(err, context.Canceled) {
+		t.Errorf("got error %v, want context.Canceled", err)
+	}
+	select {
+	case <-upstream.cancelled:
+		t.Fatal("fetch was cancelled while a caller was still waiting")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	// The last caller giving up cancels it.
+	cancel2()
+	if err := <-errs2; !errors.Is(err, context.Canceled) {
+		t.Errorf("got error %v, want context.Canceled", err)
+	}
+	select {
+	case <-upstream.cancelled:
+	case <-time.After(5 * time.Second):
+		t.Fatal("fetch was not cancelled after every caller gave up")
+	}
+
+	// A cancelled fetch isn't reported as a failed refresh, and the next
+	// caller starts a new one.
+	for _, status := range r.status() {
+		if status.LastErr != nil {
+			t.Errorf("%s: got refresh error %v after cancellation, want none", status.Name, status.LastErr)
+		}
+	}
+	close(upstream.release)
+	if err := r.refresh(context.Background()); err != nil {
+		t.Fatalf("refresh failed: %v", err)
+	}
+	if n := upstream.sizes.Load(); n != 2 {
+		t.Errorf("sizes were fetched %d times, want twice", n)
+	}
+}
+
+func TestRefresherIsolatesFailingResources(t *testing.T) {
+	upstream := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == "/v2/databases/options" {
+			w.WriteHeader(http.StatusForbidden)
+			w.Write([]byte(`{"id":"forbidden","message":"You do not have access"}`))
+			return
+		}
+		w.Write([]byte(`{"sizes":[{"slug":"s-1vcpu-1gb"}],"regions":[{"slug":"nyc1"}]}`))
+	})
+	r := newRefresher(newTestClient(t, upstream), time.Minute)
+
+	if err := r.refresh(context.Background()); err == nil {
+		t.Fatal("got no error with a failing resource")
+	}
+	cat := r.latest()
+	if len(cat.Sizes) != 1 || len(cat.Regions) != 1 {
+		t.Errorf("got %d sizes and %d regions, want the resources that succeeded", len(cat.Sizes), len(cat.Regions))
+	}
+	for _, status := range r.status() {
+		failed := status.Name == catalog.ResourceDatabaseOptions
+		if (status.LastErr != nil) != failed {
+			t.Errorf("%s: got error %v", status.Name, status.LastErr)
+		}
+		if status.RetrievedAt.IsZero() != failed {
+			t.Errorf("%s: got retrieved at %v", status.Name, status.RetrievedAt)
+		}
+	}
+}
+
+func TestCatalogForRefreshesMissingResources(t *testing.T) {
+	var requests atomic.Int32
+	upstream := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == "/v2/databases/options" {
+			w.WriteHeader(http.StatusInternalServerError)
+			w.Write([]byte(`{"id":"server_error","message":"boom"}`))
+			return
+		}
+		w.Write([]byte(`{"sizes":[{"slug":"s-1vcpu-1gb"}]}`))
+	})
+	h := &handler{
+		refresher:      newRefresher(newTestClient(t, upstream), time.Minute),
+		requestTimeout: time.Minute,
+	}
+
+	// Only the requested resource is retrieved, and a failing resource
+	// doesn't stop it being served.
+	cat, err := h.catalogFor(context.Background(), catalog.ResourceSizes)
+	if err != nil {
+		t.Fatalf("catalogFor failed: %v", err)
+	}
+	if len(cat.Sizes) != 1 {
+		t.Errorf("got %d sizes, want 1", len(cat.Sizes))
+	}
+	if n := requests.Load(); n != 1 {
+		t.Errorf("got %d upstream requests, want 1", n)
+	}
+	if _, err := h.catalogFor(context.Background(), catalog.ResourceDatabaseOptions); err == nil {
+		t.Error("got no error for a failing resource")
+	}
+
+	// Cached resources are served without going upstream.
+	requests.Store(0)
+	if _, err := h.catalogFor(context.Background(), catalog.ResourceSizes); err != nil {
+		t.Fatalf("catalogFor failed: %v", err)
+	}
+	if n := requests.Load(); n != 0 {
+		t.Errorf("got %d upstream requests for a cached resource, want none", n)
+	}
+}
+
+// captureLogs sends the default logger's output to the returned buffer until
+// the test ends.
+func captureLogs(t *testing.T) *bytes.Buffer {
+	var buf bytes.Buffer
+	prev := slog.Default()
+	slog.SetDefault(slog.New(slog.NewJSONHandler(&buf, nil)))
+	t.Cleanup(func() { slog.SetDefault(prev) })
+
+	return &buf
+}
+
+// logEntries decodes the JSON log lines in buf with the given message.
+func logEntries(t *testing.T, buf *bytes.Buffer, msg string) []map[string]interface{} {
+	t.Helper()
+	var entries []map[string]interface{}
+	dec := json.NewDecoder(buf)
+	for dec.More() {
+		var entry map[string]interface{}
+		if err := dec.Decode(&entry); err != nil {
+			t.Fatal(err)
+		}
+		if entry["msg"] == msg {
+			entries = append(entries, entry)
+		}
+	}
+
+	return entries
+}
+
+func TestRefreshLogsUpstreamRequestIDs(t *testing.T) {
+	upstream := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/v2/sizes":
+			w.Header().Set(upstreamRequestIDHeader, "do-sizes")
+			w.Write([]byte(`{"sizes":[{"slug":"s-1vcpu-1gb"}]}`))
+		default:
+			w.Header().Set(upstreamRequestIDHeader, "do-regions")
+			w.WriteHeader(http.StatusInternalServerError)
+			w.Write([]byte(`{"id":"server_error","message":"boom"}`))
+		}
+	})
+	srv := httptest.NewServer(upstream)
+	t.Cleanup(srv.Close)
+	client := godo.NewClient(&http.Client{Transport: &upstreamTransport{next: srv.Client().Transport}})
+	client.BaseURL, _ = url.Parse(srv.URL + "/")
+	h := &handler{
+		refresher:      newRefresher(client, time.Minute),
+		requestTimeout: time.Minute,
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/sizes", h.sizes)
+	mux.HandleFunc("/regions", h.regions)
+	server := withRequestID(withAccessLog(mux))
+	logs := captureLogs(t)
+
+	for _, path := range []string{"/sizes", "/regions"} {
+		server.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+
+	// Each cache miss is logged with the IDs of the calls made to fill
+	// it, even though the refresh outlives the request.
+	want := map[string]string{"/sizes": "do-sizes", "/regions": "do-regions"}
+	requests := logEntries(t, bytes.NewBuffer(logs.Bytes()), "request")
+	if len(requests) != len(want) {
+		t.Fatalf("got %d request logs, want %d", len(requests), len(want))
+	}
+	for _, entry := range requests {
+		ids, _ := entry["upstream_request_ids"].([]interface{})
+		if entry["cache"] != "miss" || len(ids) != 1 || ids[0] != want[entry["path"].(string)] {
+			t.Errorf("%s: got cache %v and upstream IDs %v, want a miss with %q", entry["path"], entry["cache"], ids, want[entry["path"].(string)])
+		}
+	}
+
+	// The failed refresh is logged with the ID too.
+	failures := logEntries(t, bytes.NewBuffer(logs.Bytes()), "refresh failed")
+	if len(failures) != 1 {
+		t.Fatalf("got %d refresh failure logs, want 1", len(failures))
+	}
+	ids, _ := failures[0]["upstream_request_ids"].([]interface{})
+	if failures[0]["resource"] != catalog.ResourceRegions || len(ids) != 1 || ids[0] != "do-regions" {
+		t.Errorf("got refresh failure %v, want regions with do-regions", failures[0])
+	}
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"encoding/json"
+	"log/slog"
+	"os"
+
+	"github.com/andrewsomething/do-api-slugs/api/internal/catalog"
+)
+
+// snapshot is the file saved at SNAPSHOT_PATH. The catalog's fields are at
+// the top level, so catalog.Load (and slugsgen -snapshot) can read it too;
+// the history built up from earlier refreshes is saved alongside them.
+type snapshot struct {
+	*catalog.Catalog
+	Prices   *priceState   `json:"price_history,omitempty"`
+	Removals *removalState `json:"removals,omitempty"`
+}
+
+// loadSnapshot seeds the refresher, price history and removals with the snapshot at
+// path, if there is one, so the catalog can be served before the first
+// refresh completes.
+func (h *handler) loadSnapshot(path string) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		slog.Warn("not loading snapshot", "path", path, "error", err)
+		return
+	}
+	var snap snapshot
+	if err := json.Unmarshal(b, &snap); err != nil || snap.Catalog == nil {
+		slog.Warn("not loading snapshot", "path", path, "error", err)
+		return
+	}
+	h.refresher.seed(snap.Catalog)
+	if snap.Prices != nil {
+		h.prices.restore(snap.Prices)
+	}
+	if snap.Removals != nil {
+		h.removals.restore(snap.Removals)
+	}
+	slog.Info("loaded snapshot", "path", path, "retrieved_at", snap.RetrievedAt)
+}
+
+// saveSnapshot saves cat and the current history to path.
+func (h *handler) saveSnapshot(cat *catalog.Catalog, path string) error {
+	return catalog.WriteJSON(path, snapshot{
+		Catalog:  cat,
+		Prices:   h.prices.state(),
+		Removals: h.removals.snapshot(),
+	})
+}
+
+// flushSnapshot saves the current catalog to path, if there is one, so it
+// isn't lost when the process exits between refreshes.
+func (h *handler) flushSnapshot(path string) {
+	cat := h.refresher.latest()
+	if cat == nil {
+		return
+	}
+	if err := h.saveSnapshot(cat, path); err != nil {
+		slog.Error("unable to save snapshot", "path", path, "error", err)
+		return
+	}
+	slog.Info("saved snapshot", "path", path, "retrieved_at", cat.RetrievedAt)
+}
+
+// snapshotSaver returns a refresh listener that saves each refreshed catalog
+// to path. It must be registered after the listeners whose history it saves.
+func (h *handler) snapshotSaver(path string) func(refreshEvent) {
+	return func(event refreshEvent) {
+		if err := h.saveSnapshot(event.Current, path); err != nil {
+			slog.Error("unable to save snapshot", "path", path, "error", err)
+		}
+	}
+}
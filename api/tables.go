@@ -0,0 +1,196 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/digitalocean/godo"
+)
+
+// listSeparator joins list values, such as a size's regions, into a single
+// table cell.
+const listSeparator = ";"
+
+func (resp sizesResponse) table() table {
+	t := table{
+		Columns: []string{
+			"slug", "description", "vcpus", "memory", "disk", "transfer",
+			"price_monthly", "price_hourly", "available", "regions",
+			"gpu_info.count", "gpu_info.model", "gpu_info.vram.amount", "gpu_info.vram.unit",
+			"disk_info",
+		},
+		Rows: [][]string{},
+	}
+	for _, s := range resp.Sizes {
+		var gpuCount, gpuModel, vramAmount, vramUnit string
+		if s.GPUInfo != nil {
+			gpuCount = strconv.Itoa(s.GPUInfo.Count)
+			gpuModel = s.GPUInfo.Model
+			if s.GPUInfo.VRAM != nil {
+				vramAmount = strconv.Itoa(s.GPUInfo.VRAM.Amount)
+				vramUnit = s.GPUInfo.VRAM.Unit
+			}
+		}
+		t.Rows = append(t.Rows, []string{
+			s.Slug,
+			s.Description,
+			strconv.Itoa(s.Vcpus),
+			strconv.Itoa(s.Memory),
+			strconv.Itoa(s.Disk),
+			formatFloat(s.Transfer),
+			formatFloat(s.PriceMonthly),
+			formatFloat(s.PriceHourly),
+			strconv.FormatBool(s.Available),
+			joinList(s.Regions),
+			gpuCount,
+			gpuModel,
+			vramAmount,
+			vramUnit,
+			formatDiskInfo(s.DiskInfo),
+		})
+	}
+
+	return t
+}
+
+func (resp regionsResponse) table() table {
+	t := table{
+		Columns: []string{"slug", "name", "available", "sizes", "features"},
+		Rows:    [][]string{},
+	}
+	for _, r := range resp.Regions {
+		t.Rows = append(t.Rows, []string{
+			r.Slug,
+			r.Name,
+			strconv.FormatBool(r.Available),
+			joinList(r.Sizes),
+			joinList(r.Features),
+		})
+	}
+
+	return t
+}
+
+func (resp imageResponse) table() table {
+	t := table{
+		Columns: []string{
+			"id", "slug", "name", "distribution", "type", "public", "status",
+			"regions", "min_disk_size", "size_gigabytes", "created_at",
+			"description", "tags",
+		},
+		Rows: [][]string{},
+	}
+	for _, i := range resp.Images {
+		t.Rows = append(t.Rows, []string{
+			strconv.Itoa(i.ID),
+			i.Slug,
+			i.Name,
+			i.Distribution,
+			i.Type,
+			strconv.FormatBool(i.Public),
+			i.Status,
+			joinList(i.Regions),
+			strconv.Itoa(i.MinDiskSize),
+			formatFloat(i.SizeGigaBytes),
+			i.Created,
+			i.Description,
+			joinList(i.Tags),
+		})
+	}
+
+	return t
+}
+
+func (resp appInstanceSizesResponse) table() table {
+	t := table{
+		Columns: []string{
+			"slug", "name", "cpu_type", "cpus", "memory_bytes", "usd_per_month",
+			"usd_per_second", "tier_slug", "scalable", "single_instance_only",
+			"deprecation_intent", "bandwidth_allowance_gib",
+		},
+		Rows: [][]string{},
+	}
+	for _, s := range resp.Sizes {
+		t.Rows = append(t.Rows, []string{
+			s.Slug,
+			s.Name,
+			string(s.CPUType),
+			s.CPUs,
+			s.MemoryBytes,
+			s.USDPerMonth,
+			s.USDPerSecond,
+			s.TierSlug,
+			strconv.FormatBool(s.Scalable),
+			strconv.FormatBool(s.SingleInstanceOnly),
+			strconv.FormatBool(s.DeprecationIntent),
+			s.BandwidthAllowanceGib,
+		})
+	}
+
+	return t
+}
+
+func (resp databaseSizesResponse) table() table {
+	t := table{
+		Columns: []string{"slug", "description", "engines", "nodes"},
+		Rows:    [][]string{},
+	}
+	for _, s := range resp.Sizes {
+		nodes := make([]string, 0, len(s.Nodes))
+		for _, n := range s.Nodes {
+			nodes = append(nodes, strconv.Itoa(n))
+		}
+		t.Rows = append(t.Rows, []string{
+			s.Slug,
+			s.Description,
+			joinList(s.Engines),
+			joinList(nodes),
+		})
+	}
+
+	return t
+}
+
+func joinList(list []string) string {
+	return strings.Join(list, listSeparator)
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
+
+// formatDiskInfo renders disks as "<type>:<amount> <unit>", e.g.
+// "local:25 gib;scratch:720 gib".
+func formatDiskInfo(disks []godo.DiskInfo) string {
+	list := make([]string, 0, len(disks))
+	for _, d := range disks {
+		if d.Size == nil {
+			list = append(list, d.Type)
+			continue
+		}
+		list = append(list, fmt.Sprintf("%s:%d %s", d.Type, d.Size.Amount, d.Size.Unit))
+	}
+
+	return joinList(list)
+}
+
+func (resp sizesResponse) keyColumns() []string {
+	return []string{"slug", "description", "vcpus", "memory", "disk", "price_monthly", "price_hourly"}
+}
+
+func (resp regionsResponse) keyColumns() []string {
+	return []string{"slug", "name", "available"}
+}
+
+func (resp imageResponse) keyColumns() []string {
+	return []string{"slug", "name", "distribution", "status"}
+}
+
+func (resp appInstanceSizesResponse) keyColumns() []string {
+	return []string{"slug", "name", "cpus", "memory_bytes", "usd_per_month"}
+}
+
+func (resp databaseSizesResponse) keyColumns() []string {
+	return []string{"slug", "description", "engines", "nodes"}
+}
@@ -0,0 +1,306 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/andrewsomething/do-api-slugs/api/internal/catalog"
+	"github.com/digitalocean/godo"
+)
+
+const (
+	maxValidateBody  = 1 << 20
+	maxValidateItems = 1000
+)
+
+// validateItem is a single slug to check. Region is optional; when set, the
+// slug must also be usable in that region.
+type validateItem struct {
+	Kind   string `json:"kind"`
+	Slug   string `json:"slug"`
+	Region string `json:"region,omitempty"`
+}
+
+type validateResult struct {
+	validateItem
+	Valid     bool     `json:"valid"`
+	Available bool     `json:"available"`
+	Reasons   []string `json:"reasons,omitempty"`
+}
+
+type validateRequest struct {
+	Items []validateItem `json:"items"`
+}
+
+type validateResponse struct {
+	Valid       bool             `json:"valid"`
+	Results     []validateResult `json:"results"`
+	RetrievedAt string           `json:"retrieved_at"`
+}
+
+// validator checks a single item against the catalog. Valid reports whether
+// the slug exists; Available whether it can currently be used, in the
+// requested region if any.
+type validator func(c *catalog.Catalog, item validateItem) validateResult
+
+// validatorResources lists the resources each validator reads, besides the
+// regions needed to check items with a region.
+var validatorResources = map[string][]string{
+	"size":              {catalog.ResourceSizes},
+	"image":             {catalog.ResourceAppImages, catalog.ResourceDistroImages},
+	"region":            {catalog.ResourceRegions},
+	"k8s_version":       {catalog.ResourceK8sOptions},
+	"db_engine_version": {catalog.ResourceDatabaseOptions},
+	"app_instance_size": {catalog.ResourceAppInstanceSizes},
+}
+
+var validators = map[string]validator{
+	"size":              validateSize,
+	"image":             validateImage,
+	"region":            validateRegion,
+	"k8s_version":       validateK8sVersion,
+	"db_engine_version": validateDatabaseEngineVersion,
+	"app_instance_size": validateAppInstanceSize,
+}
+
+func (h *handler) validate(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		writeJSONError(w, http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req validateRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxValidateBody)).Decode(&req); err != nil {
+		writeJSONError(w, http.StatusBadRequest)
+		return
+	}
+	if len(req.Items) > maxValidateItems {
+		writeJSONError(w, http.StatusRequestEntityTooLarge)
+		return
+	}
+
+	resources := validateResources(req.Items)
+	cat, err := h.catalogFor(r.Context(), resources...)
+	if err != nil {
+		h.writeUpstreamError(w, r, err)
+		return
+	}
+
+	resp := validateResponse{
+		Valid:       true,
+		Results:     make([]validateResult, 0, len(req.Items)),
+		RetrievedAt: retrievedAt(cat, resources...),
+	}
+	for _, item := range req.Items {
+		result := validateResult{validateItem: item}
+		if fn, ok := validators[item.Kind]; ok {
+			result = fn(cat, item)
+		} else {
+			result.Reasons = []string{fmt.Sprintf("unknown kind %q", item.Kind)}
+		}
+		if !result.Valid || !result.Available {
+			resp.Valid = false
+		}
+		resp.Results = append(resp.Results, result)
+	}
+
+	writeResponse(w, r, resp)
+}
+
+// validateResources returns the resources needed to validate items, so that
+// a failing resource only fails the requests that need it.
+func validateResources(items []validateItem) []string {
+	var resources []string
+	for _, item := range items {
+		needed := validatorResources[item.Kind]
+		if item.Region != "" {
+			needed = append(needed[:len(needed):len(needed)], catalog.ResourceRegions)
+		}
+		for _, resource := range needed {
+			if !contains(resources, resource) {
+				resources = append(resources, resource)
+			}
+		}
+	}
+
+	return resources
+}
+
+// checkRegion verifies that item.Region exists and is listed in regions. It
+// is a no-op if no region was requested.
+func checkRegion(c *catalog.Catalog, result *validateResult, regions []string) {
+	if result.Region == "" {
+		return
+	}
+	if !regionExists(c, result.Region) {
+		result.Available = false
+		result.Reasons = append(result.Reasons, fmt.Sprintf("unknown region %q", result.Region))
+		return
+	}
+	if !contains(regions, result.Region) {
+		result.Available = false
+		result.Reasons = append(result.Reasons, fmt.Sprintf("%s %q is not available in %s", result.Kind, result.Slug, result.Region))
+	}
+}
+
+func validateSize(c *catalog.Catalog, item validateItem) validateResult {
+	result := validateResult{validateItem: item}
+	for _, s := range c.Sizes {
+		if s.Slug != item.Slug {
+			continue
+		}
+		result.Valid = true
+		result.Available = s.Available
+		if !s.Available {
+			result.Reasons = append(result.Reasons, fmt.Sprintf("size %q is not available", item.Slug))
+		}
+		checkRegion(c, &result, s.Regions)
+		return result
+	}
+
+	result.Reasons = []string{fmt.Sprintf("unknown size %q", item.Slug)}
+	return result
+}
+
+func validateImage(c *catalog.Catalog, item validateItem) validateResult {
+	result := validateResult{validateItem: item}
+	for _, images := range [][]godo.Image{c.AppImages, c.DistroImages} {
+		for _, i := range images {
+			if i.Slug != item.Slug {
+				continue
+			}
+			result.Valid = true
+			result.Available = i.Status == "" || i.Status == imageStatusAvailable
+			if !result.Available {
+				result.Reasons = append(result.Reasons, fmt.Sprintf("image %q is %s", item.Slug, i.Status))
+			}
+			checkRegion(c, &result, i.Regions)
+			return result
+		}
+	}
+
+	result.Reasons = []string{fmt.Sprintf("unknown image %q", item.Slug)}
+	return result
+}
+
+func validateRegion(c *catalog.Catalog, item validateItem) validateResult {
+	result := validateResult{validateItem: item}
+	for _, r := range c.Regions {
+		if r.Slug != item.Slug {
+			continue
+		}
+		result.Valid = true
+		result.Available = r.Available
+		if !r.Available {
+			result.Reasons = append(result.Reasons, fmt.Sprintf("region %q is not available", item.Slug))
+		}
+		return result
+	}
+
+	result.Reasons = []string{fmt.Sprintf("unknown region %q", item.Slug)}
+	return result
+}
+
+func validateK8sVersion(c *catalog.Catalog, item validateItem) validateResult {
+	result := validateResult{validateItem: item}
+	if c.K8sOptions == nil {
+		result.Reasons = []string{"Kubernetes options are unavailable"}
+		return result
+	}
+	for _, v := range c.K8sOptions.Versions {
+		if v.Slug != item.Slug {
+			continue
+		}
+		result.Valid = true
+		result.Available = true
+		var regions []string
+		for _, r := range c.K8sOptions.Regions {
+			regions = append(regions, r.Slug)
+		}
+		checkRegion(c, &result, regions)
+		return result
+	}
+
+	result.Reasons = []string{fmt.Sprintf("unknown Kubernetes version %q", item.Slug)}
+	return result
+}
+
+// validateDatabaseEngineVersion checks slugs of the form "<engine>-<version>",
+// e.g. "pg-16" or "mysql-8".
+func validateDatabaseEngineVersion(c *catalog.Catalog, item validateItem) validateResult {
+	result := validateResult{validateItem: item}
+	engine, version, ok := strings.Cut(item.Slug, "-")
+	if !ok {
+		result.Reasons = []string{fmt.Sprintf("database engine version %q must be of the form <engine>-<version>", item.Slug)}
+		return result
+	}
+	options, ok := c.DatabaseOptions[engine].(map[string]interface{})
+	if !ok {
+		result.Reasons = []string{fmt.Sprintf("unknown database engine %q", engine)}
+		return result
+	}
+	if !contains(stringSlice(options["versions"]), version) {
+		result.Reasons = []string{fmt.Sprintf("unknown %s version %q", engine, version)}
+		return result
+	}
+
+	result.Valid = true
+	result.Available = true
+	checkRegion(c, &result, stringSlice(options["regions"]))
+	return result
+}
+
+func validateAppInstanceSize(c *catalog.Catalog, item validateItem) validateResult {
+	result := validateResult{validateItem: item}
+	for _, s := range c.AppInstanceSizes {
+		if s.Slug != item.Slug {
+			continue
+		}
+		result.Valid = true
+		result.Available = !s.DeprecationIntent
+		if s.DeprecationIntent {
+			result.Reasons = append(result.Reasons, fmt.Sprintf("app instance size %q is scheduled for deprecation", item.Slug))
+		}
+		return result
+	}
+
+	result.Reasons = []string{fmt.Sprintf("unknown app instance size %q", item.Slug)}
+	return result
+}
+
+func regionExists(c *catalog.Catalog, slug string) bool {
+	for _, r := range c.Regions {
+		if r.Slug == slug {
+			return true
+		}
+	}
+
+	return false
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+
+	return false
+}
+
+// stringSlice converts a decoded JSON array to a slice of strings, skipping
+// any non-string elements.
+func stringSlice(v interface{}) []string {
+	items, _ := v.([]interface{})
+	list := make([]string, 0, len(items))
+	for _, i := range items {
+		if s, ok := i.(string); ok {
+			list = append(list, s)
+		}
+	}
+
+	return list
+}
@@ -0,0 +1,211 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/andrewsomething/do-api-slugs/api/internal/catalog"
+	"github.com/digitalocean/godo"
+)
+
+func validateCatalog() *catalog.Catalog {
+	return &catalog.Catalog{
+		Sizes: []godo.Size{
+			{Slug: "s-1vcpu-1gb", Available: true, Regions: []string{"nyc1", "ams3"}},
+			{Slug: "s-old", Available: false, Regions: []string{"nyc1"}},
+		},
+		Regions: []godo.Region{
+			{Slug: "nyc1", Available: true},
+			{Slug: "ams3", Available: true},
+			{Slug: "sfo1", Available: false},
+		},
+		DistroImages: []godo.Image{{Slug: "ubuntu-24-04-x64", Status: imageStatusAvailable, Regions: []string{"nyc1"}}},
+		K8sOptions: &godo.KubernetesOptions{
+			Versions: []*godo.KubernetesVersion{{Slug: "1.31.1-do.0"}},
+			Regions:  []*godo.KubernetesRegion{{Slug: "nyc1"}},
+		},
+		DatabaseOptions: map[string]interface{}{
+			"pg": map[string]interface{}{"versions": []interface{}{"16"}, "regions": []interface{}{"nyc1"}},
+		},
+		AppInstanceSizes: []godo.AppInstanceSize{{Slug: "apps-s-1vcpu-0.5gb"}},
+		RetrievedAt:      time.Now(),
+	}
+}
+
+func TestValidateItems(t *testing.T) {
+	tests := []struct {
+		name string
+		item validateItem
+		want validateResult
+	}{
+		{
+			name: "valid size",
+			item: validateItem{Kind: "size", Slug: "s-1vcpu-1gb", Region: "nyc1"},
+			want: validateResult{Valid: true, Available: true},
+		},
+		{
+			name: "unknown size",
+			item: validateItem{Kind: "size", Slug: "s-typo"},
+			want: validateResult{Reasons: []string{`unknown size "s-typo"`}},
+		},
+		{
+			name: "unavailable size",
+			item: validateItem{Kind: "size", Slug: "s-old"},
+			want: validateResult{Valid: true, Reasons: []string{`size "s-old" is not available`}},
+		},
+		{
+			name: "size not offered in region",
+			item: validateItem{Kind: "size", Slug: "s-1vcpu-1gb", Region: "sfo1"},
+			want: validateResult{Valid: true, Reasons: []string{`size "s-1vcpu-1gb" is not available in sfo1`}},
+		},
+		{
+			name: "size in unknown region",
+			item: validateItem{Kind: "size", Slug: "s-1vcpu-1gb", Region: "xyz9"},
+			want: validateResult{Valid: true, Reasons: []string{`unknown region "xyz9"`}},
+		},
+		{
+			name: "valid region",
+			item: validateItem{Kind: "region", Slug: "ams3"},
+			want: validateResult{Valid: true, Available: true},
+		},
+		{
+			name: "unknown region",
+			item: validateItem{Kind: "region", Slug: "xyz9"},
+			want: validateResult{Reasons: []string{`unknown region "xyz9"`}},
+		},
+		{
+			name: "unavailable region",
+			item: validateItem{Kind: "region", Slug: "sfo1"},
+			want: validateResult{Valid: true, Reasons: []string{`region "sfo1" is not available`}},
+		},
+		{
+			name: "image not offered in region",
+			item: validateItem{Kind: "image", Slug: "ubuntu-24-04-x64", Region: "ams3"},
+			want: validateResult{Valid: true, Reasons: []string{`image "ubuntu-24-04-x64" is not available in ams3`}},
+		},
+		{
+			name: "valid Kubernetes version",
+			item: validateItem{Kind: "k8s_version", Slug: "1.31.1-do.0", Region: "nyc1"},
+			want: validateResult{Valid: true, Available: true},
+		},
+		{
+			name: "valid database engine version",
+			item: validateItem{Kind: "db_engine_version", Slug: "pg-16"},
+			want: validateResult{Valid: true, Available: true},
+		},
+		{
+			name: "unknown database engine version",
+			item: validateItem{Kind: "db_engine_version", Slug: "pg-9"},
+			want: validateResult{Reasons: []string{`unknown pg version "9"`}},
+		},
+		{
+			name: "valid app instance size",
+			item: validateItem{Kind: "app_instance_size", Slug: "apps-s-1vcpu-0.5gb"},
+			want: validateResult{Valid: true, Available: true},
+		},
+	}
+	cat := validateCatalog()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.want.validateItem = tt.item
+			got := validators[tt.item.Kind](cat, tt.item)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name      string
+		items     []validateItem
+		wantValid bool
+	}{
+		{
+			name: "valid spec",
+			items: []validateItem{
+				{Kind: "size", Slug: "s-1vcpu-1gb", Region: "nyc1"},
+				{Kind: "image", Slug: "ubuntu-24-04-x64", Region: "nyc1"},
+				{Kind: "region", Slug: "nyc1"},
+			},
+			wantValid: true,
+		},
+		{
+			name: "one invalid item",
+			items: []validateItem{
+				{Kind: "size", Slug: "s-1vcpu-1gb", Region: "nyc1"},
+				{Kind: "size", Slug: "s-typo"},
+			},
+		},
+		{
+			name:  "unknown kind",
+			items: []validateItem{{Kind: "volume", Slug: "vol-1"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := &handler{refresher: newRefresher(nil, time.Minute)}
+			h.refresher.seed(validateCatalog())
+			body, _ := json.Marshal(validateRequest{Items: tt.items})
+
+			rec := httptest.NewRecorder()
+			h.validate(rec, httptest.NewRequest(http.MethodPost, "/validate", bytes.NewReader(body)))
+
+			if rec.Code != http.StatusOK {
+				t.Fatalf("got status %d, want %d", rec.Code, http.StatusOK)
+			}
+			var resp validateResponse
+			if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+				t.Fatal(err)
+			}
+			if resp.Valid != tt.wantValid {
+				t.Errorf("got valid %v, want %v", resp.Valid, tt.wantValid)
+			}
+			if len(resp.Results) != len(tt.items) {
+				t.Errorf("got %d results, want %d", len(resp.Results), len(tt.items))
+			}
+		})
+	}
+}
+
+func TestValidateRejectsBadRequests(t *testing.T) {
+	tests := []struct {
+		name   string
+		method string
+		body   string
+		status int
+	}{
+		{name: "wrong method", method: http.MethodGet, status: http.StatusMethodNotAllowed},
+		{name: "invalid JSON", method: http.MethodPost, body: "{", status: http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := &handler{refresher: newRefresher(nil, time.Minute)}
+
+			rec := httptest.NewRecorder()
+			h.validate(rec, httptest.NewRequest(tt.method, "/validate", bytes.NewReader([]byte(tt.body))))
+
+			if rec.Code != tt.status {
+				t.Errorf("got status %d, want %d", rec.Code, tt.status)
+			}
+		})
+	}
+}
+
+func TestValidateResources(t *testing.T) {
+	got := validateResources([]validateItem{
+		{Kind: "size", Slug: "s-1vcpu-1gb"},
+		{Kind: "size", Slug: "s-2vcpu-2gb", Region: "nyc1"},
+		{Kind: "db_engine_version", Slug: "pg-16"},
+	})
+	want := []string{catalog.ResourceSizes, catalog.ResourceRegions, catalog.ResourceDatabaseOptions}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,248 @@
+package main
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"log/slog"
+	"math/big"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	webhookEventType      = "catalog.changed"
+	webhookMaxAttempts    = 5
+	webhookBaseBackoff    = 2 * time.Second
+	webhookTimeout        = 10 * time.Second
+	webhookDeliveryLogLen = 100
+
+	webhookSignatureHeader = "X-Slugs-Signature"
+	webhookTimestampHeader = "X-Slugs-Timestamp"
+	webhookEventHeader     = "X-Slugs-Event-ID"
+)
+
+// webhookEvent is the payload POSTed to every configured webhook URL when a
+// refresh detects changes to the catalog.
+type webhookEvent struct {
+	ID          string    `json:"id"`
+	Type        string    `json:"type"`
+	CreatedAt   time.Time `json:"created_at"`
+	RetrievedAt time.Time `json:"retrieved_at"`
+	Changes     []change  `json:"changes"`
+}
+
+// webhookDelivery records the outcome of a single delivery attempt. Only the
+// host of the receiving URL is kept, as webhook paths often embed secrets.
+type webhookDelivery struct {
+	EventID     string    `json:"event_id"`
+	Host        string    `json:"host"`
+	Attempt     int       `json:"attempt"`
+	StatusCode  int       `json:"status_code,omitempty"`
+	Error       string    `json:"error,omitempty"`
+	Delivered   bool      `json:"delivered"`
+	AttemptedAt time.Time `json:"attempted_at"`
+	Duration    string    `json:"duration"`
+}
+
+type webhookDeliveriesResponse struct {
+	Deliveries  []webhookDelivery `json:"deliveries"`
+	RetrievedAt string            `json:"retrieved_at"`
+}
+
+// notifier delivers webhook events. Payloads are signed with an HMAC-SHA256
+// of "<timestamp>.<body>" using the shared secret, sent hex encoded in the
+// X-Slugs-Signature header as "sha256=<digest>".
+type notifier struct {
+	client      *http.Client
+	urls        []string
+	secret      []byte
+	maxAttempts int
+	backoff     time.Duration
+
+	mu         sync.Mutex
+	deliveries []webhookDelivery
+}
+
+func newNotifier(urls []string, secret string) *notifier {
+	return &notifier{
+		client:      &http.Client{Timeout: webhookTimeout},
+		urls:        urls,
+		secret:      []byte(secret),
+		maxAttempts: webhookMaxAttempts,
+		backoff:     webhookBaseBackoff,
+	}
+}
+
+// notify sends an event describing the refresh to every webhook URL. Each
+// URL is delivered to in its own goroutine so a slow receiver can't hold up
+// the others.
+func (n *notifier) notify(event refreshEvent) {
+	if len(n.urls) == 0 || len(event.Changes) == 0 {
+		return
+	}
+
+	id, err := newID()
+	if err != nil {
+		slog.Error("unable to create webhook event", "error", err)
+		return
+	}
+	body, err := json.Marshal(webhookEvent{
+		ID:          id,
+		Type:        webhookEventType,
+		CreatedAt:   time.Now().UTC(),
+		RetrievedAt: event.Current.RetrievedAt,
+		Changes:     event.Changes,
+	})
+	if err != nil {
+		slog.Error("unable to encode webhook event", "event_id", id, "error", err)
+		return
+	}
+
+	for _, u := range n.urls {
+		go n.deliver(id, u, body)
+	}
+}
+
+// deliver POSTs body to u, retrying with jittered exponential backoff on
+// network errors, 408, 429 and 5xx responses.
+func (n *notifier) deliver(id, u string, body []byte) {
+	host := u
+	if parsed, err := url.Parse(u); err == nil {
+		host = parsed.Host
+	}
+
+	for attempt := 1; attempt <= n.maxAttempts; attempt++ {
+		start := time.Now()
+		status, err := n.post(id, u, body)
+		d := webhookDelivery{
+			EventID:     id,
+			Host:        host,
+			Attempt:     attempt,
+			StatusCode:  status,
+			Delivered:   err == nil && status >= 200 && status < 300,
+			AttemptedAt: start.UTC(),
+			Duration:    time.Since(start).String(),
+		}
+		if err != nil {
+			d.Error = err.Error()
+		}
+		n.record(d)
+
+		if d.Delivered || !retryable(status, err) {
+			if !d.Delivered {
+				slog.Warn("webhook delivery failed", "event_id", id, "host", host, "status", status)
+			}
+			return
+		}
+		if attempt < n.maxAttempts {
+			time.Sleep(n.backoffFor(attempt))
+		}
+	}
+
+	slog.Warn("webhook delivery failed, giving up", "event_id", id, "host", host, "attempts", n.maxAttempts)
+}
+
+func (n *notifier) post(id, u string, body []byte) (int, error) {
+	req, err := http.NewRequest(http.MethodPost, u, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(webhookEventHeader, id)
+	req.Header.Set(webhookTimestampHeader, timestamp)
+	if len(n.secret) > 0 {
+		req.Header.Set(webhookSignatureHeader, "sha256="+signPayload(n.secret, timestamp, body))
+	}
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	resp.Body.Close()
+
+	return resp.StatusCode, nil
+}
+
+// backoffFor returns the delay before retrying after the given attempt: the
+// base backoff doubled for each previous attempt, plus up to 50% jitter.
+func (n *notifier) backoffFor(attempt int) time.Duration {
+	d := n.backoff << (attempt - 1)
+	jitter, err := rand.Int(rand.Reader, big.NewInt(int64(d/2)+1))
+	if err != nil {
+		return d
+	}
+
+	return d + time.Duration(jitter.Int64())
+}
+
+func (n *notifier) record(d webhookDelivery) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.deliveries = append(n.deliveries, d)
+	if len(n.deliveries) > webhookDeliveryLogLen {
+		n.deliveries = n.deliveries[len(n.deliveries)-webhookDeliveryLogLen:]
+	}
+}
+
+// recent returns the logged delivery attempts, newest first.
+func (n *notifier) recent() []webhookDelivery {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	list := make([]webhookDelivery, 0, len(n.deliveries))
+	for i := len(n.deliveries) - 1; i >= 0; i-- {
+		list = append(list, n.deliveries[i])
+	}
+
+	return list
+}
+
+func retryable(status int, err error) bool {
+	if err != nil {
+		return true
+	}
+
+	return status == http.StatusRequestTimeout ||
+		status == http.StatusTooManyRequests ||
+		status >= 500
+}
+
+// signPayload returns the hex encoded HMAC-SHA256 of "<timestamp>.<body>".
+// Including the timestamp lets receivers reject replayed deliveries.
+func signPayload(secret []byte, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// newID returns a random hex identifier for events and requests.
+func newID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(b), nil
+}
+
+func (h *handler) webhookDeliveries(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Cache-Control", "no-store")
+	timestamp := time.Now().Format("Mon Jan _2 15:04:05 2006 UTC")
+	resp := webhookDeliveriesResponse{
+		Deliveries:  h.notifier.recent(),
+		RetrievedAt: timestamp,
+	}
+
+	writeResponse(w, r, resp)
+}
@@ -0,0 +1,191 @@
+package main
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/andrewsomething/do-api-slugs/api/internal/catalog"
+)
+
+// webhookReceiver is an httptest server that answers deliveries with the
+// given statuses in turn, repeating the last, and keeps the requests.
+type webhookReceiver struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	statuses []int
+	requests []*http.Request
+	bodies   [][]byte
+}
+
+func newWebhookReceiver(t *testing.T, statuses ...int) *webhookReceiver {
+	rcv := &webhookReceiver{statuses: statuses}
+	rcv.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		rcv.mu.Lock()
+		defer rcv.mu.Unlock()
+		status := rcv.statuses[min(len(rcv.requests), len(rcv.statuses)-1)]
+		rcv.requests = append(rcv.requests, r)
+		rcv.bodies = append(rcv.bodies, body)
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(rcv.Close)
+
+	return rcv
+}
+
+func TestNotifierDeliver(t *testing.T) {
+	tests := []struct {
+		name      string
+		statuses  []int
+		attempts  int
+		delivered bool
+	}{
+		{name: "delivered", statuses: []int{http.StatusNoContent}, attempts: 1, delivered: true},
+		{name: "retried after server errors", statuses: []int{http.StatusInternalServerError, http.StatusBadGateway, http.StatusOK}, attempts: 3, delivered: true},
+		{name: "retried after rate limit", statuses: []int{http.StatusTooManyRequests, http.StatusOK}, attempts: 2, delivered: true},
+		{name: "client error not retried", statuses: []int{http.StatusBadRequest}, attempts: 1},
+		{name: "gives up", statuses: []int{http.StatusServiceUnavailable}, attempts: 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rcv := newWebhookReceiver(t, tt.statuses...)
+			n := newNotifier([]string{rcv.URL + "/hooks/secret-token"}, "s3cret")
+			n.maxAttempts = 3
+			n.backoff = time.Millisecond
+
+			n.deliver("event-1", n.urls[0], []byte(`{"id":"event-1"}`))
+
+			if len(rcv.requests) != tt.attempts {
+				t.Fatalf("got %d requests, want %d", len(rcv.requests), tt.attempts)
+			}
+			log := n.recent()
+			if len(log) != tt.attempts {
+				t.Fatalf("got %d logged attempts, want %d", len(log), tt.attempts)
+			}
+			host := rcv.Listener.Addr().String()
+			for i, d := range log {
+				if want := tt.attempts - i; d.Attempt != want {
+					t.Errorf("log entry %d is attempt %d, want %d", i, d.Attempt, want)
+				}
+				if d.Host != host || d.EventID != "event-1" {
+					t.Errorf("got host %q and event %q, want %q and event-1", d.Host, d.EventID, host)
+				}
+			}
+			if log[0].Delivered != tt.delivered {
+				t.Errorf("got delivered %t, want %t", log[0].Delivered, tt.delivered)
+			}
+		})
+	}
+}
+
+func TestNotifierSignature(t *testing.T) {
+	tests := []struct {
+		name   string
+		secret string
+	}{
+		{name: "signed", secret: "s3cret"},
+		{name: "unsigned"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rcv := newWebhookReceiver(t, http.StatusOK)
+			n := newNotifier([]string{rcv.URL}, tt.secret)
+			body := []byte(`{"id":"event-1"}`)
+
+			n.deliver("event-1", rcv.URL, body)
+
+			r := rcv.requests[0]
+			if got := r.Header.Get(webhookEventHeader); got != "event-1" {
+				t.Errorf("got event ID %q, want event-1", got)
+			}
+			timestamp := r.Header.Get(webhookTimestampHeader)
+			if timestamp == "" {
+				t.Fatal("missing timestamp")
+			}
+			signature := r.Header.Get(webhookSignatureHeader)
+			if tt.secret == "" {
+				if signature != "" {
+					t.Errorf("got signature %q without a secret", signature)
+				}
+				return
+			}
+
+			// Verify the way a receiver would, independently of signPayload.
+			mac := hmac.New(sha256.New, []byte(tt.secret))
+			mac.Write([]byte(timestamp + "." + string(rcv.bodies[0])))
+			want := "sha256=" + hex.EncodeToString(mac.Sum(nil))
+			if !hmac.Equal([]byte(signature), []byte(want)) {
+				t.Errorf("got signature %q, want %q", signature, want)
+			}
+		})
+	}
+}
+
+func TestNotifierNotify(t *testing.T) {
+	received := make(chan webhookEvent, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var event webhookEvent
+		json.NewDecoder(r.Body).Decode(&event)
+		received <- event
+	}))
+	defer srv.Close()
+
+	n := newNotifier([]string{srv.URL}, "")
+	at := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	// Refreshes without changes aren't delivered.
+	n.notify(refreshEvent{Current: &catalog.Catalog{RetrievedAt: at}})
+
+	changes := []change{{Resource: "size", Slug: "s-1", Action: changeAdded}}
+	n.notify(refreshEvent{Current: &catalog.Catalog{RetrievedAt: at}, Changes: changes})
+
+	select {
+	case event := <-received:
+		if event.Type != webhookEventType || !event.RetrievedAt.Equal(at) || len(event.Changes) != 1 || event.Changes[0].Slug != "s-1" {
+			t.Errorf("got event %+v", event)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("no event delivered")
+	}
+	select {
+	case event := <-received:
+		t.Errorf("got unexpected event %+v", event)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestNotifierBackoff(t *testing.T) {
+	n := newNotifier(nil, "")
+	n.backoff = time.Second
+	for attempt, base := range map[int]time.Duration{1: time.Second, 2: 2 * time.Second, 3: 4 * time.Second} {
+		for range 20 {
+			if d := n.backoffFor(attempt); d < base || d > base+base/2 {
+				t.Errorf("attempt %d: got backoff %v, want between %v and %v", attempt, d, base, base+base/2)
+			}
+		}
+	}
+}
+
+func TestNotifierDeliveryLog(t *testing.T) {
+	n := newNotifier(nil, "")
+	for i := range webhookDeliveryLogLen + 10 {
+		n.record(webhookDelivery{Attempt: i})
+	}
+
+	log := n.recent()
+	if len(log) != webhookDeliveryLogLen {
+		t.Fatalf("got %d deliveries, want %d", len(log), webhookDeliveryLogLen)
+	}
+	if first, last := log[0].Attempt, log[len(log)-1].Attempt; first != webhookDeliveryLogLen+9 || last != 10 {
+		t.Errorf("got attempts %d to %d, want newest first from %d to 10", first, last, webhookDeliveryLogLen+9)
+	}
+}